@@ -0,0 +1,197 @@
+package anypoint
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	application_manager_v2 "github.com/mulesoft-anypoint/anypoint-client-go/application_manager_v2"
+)
+
+// prefix of the secure properties values stored in the state
+const APP_DEPLOYMENT_V2_SECURE_PROP_HASH_PREFIX = "sha256:"
+
+const APP_DEPLOYMENT_V2_SECURE_PROPS_PATH = "application.0.configuration.0.mule_agent_app_props_service.0.secure_properties"
+
+/*
+Returns a salted hash of the given secure property value in the format sha256:<salt>:<digest>.
+The secure properties are returned masked by the API, the hash is stored in the state instead
+so that changes to the configured values can still be detected.
+*/
+func hashAppDeploymentV2SecureProp(value string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("unable to generate secure property salt: %s", err)
+	}
+	return saltedHashAppDeploymentV2SecureProp(hex.EncodeToString(salt), value), nil
+}
+
+func saltedHashAppDeploymentV2SecureProp(salt, value string) string {
+	sum := sha256.Sum256([]byte(salt + value))
+	return APP_DEPLOYMENT_V2_SECURE_PROP_HASH_PREFIX + salt + ":" + hex.EncodeToString(sum[:])
+}
+
+// returns true if the given value is a secure property hash as stored in the state
+func isAppDeploymentV2SecurePropHash(value string) bool {
+	if !strings.HasPrefix(value, APP_DEPLOYMENT_V2_SECURE_PROP_HASH_PREFIX) {
+		return false
+	}
+	parts := strings.Split(strings.TrimPrefix(value, APP_DEPLOYMENT_V2_SECURE_PROP_HASH_PREFIX), ":")
+	return len(parts) == 2 && len(parts[1]) == sha256.Size*2
+}
+
+// returns true if the given hash was computed from the given value
+func matchAppDeploymentV2SecurePropHash(hash, value string) bool {
+	if !isAppDeploymentV2SecurePropHash(hash) {
+		return false
+	}
+	salt := strings.Split(strings.TrimPrefix(hash, APP_DEPLOYMENT_V2_SECURE_PROP_HASH_PREFIX), ":")[0]
+	expected := saltedHashAppDeploymentV2SecureProp(salt, value)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(expected)) == 1
+}
+
+// suppresses the diff of a secure property when the configured value matches the hash stored in the state
+func diffSuppressAppDeploymentV2SecureProp(k, old, new string, d *schema.ResourceData) bool {
+	if strings.HasSuffix(k, ".%") {
+		return false
+	}
+	return matchAppDeploymentV2SecurePropHash(old, new)
+}
+
+/*
+Replaces the masked secure properties returned by the API with salted hashes.
+Values already hashed in the state are kept, values known in clear (i.e. right after creation or update) are hashed.
+Properties unknown to the state (i.e. on import) are kept as returned by the API.
+*/
+func hashAppDeploymentV2SecureProps(d *schema.ResourceData, data map[string]interface{}) error {
+	props_service_d := getAppDeploymentV2PropsServiceData(data)
+	if props_service_d == nil {
+		return nil
+	}
+	secure_properties, ok := props_service_d["secure_properties"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	current, _ := d.Get(APP_DEPLOYMENT_V2_SECURE_PROPS_PATH).(map[string]interface{})
+	result := make(map[string]interface{}, len(secure_properties))
+	for k, v := range secure_properties {
+		val, ok := current[k].(string)
+		if !ok {
+			result[k] = v
+		} else if isAppDeploymentV2SecurePropHash(val) {
+			result[k] = val
+		} else {
+			hash, err := hashAppDeploymentV2SecureProp(val)
+			if err != nil {
+				return err
+			}
+			result[k] = hash
+		}
+	}
+	props_service_d["secure_properties"] = result
+	return nil
+}
+
+// returns the mule_agent_app_props_service block of the given flattened deployment if it exists
+func getAppDeploymentV2PropsServiceData(data map[string]interface{}) map[string]interface{} {
+	path := []string{"application", "configuration", "mule_agent_app_props_service"}
+	node := data
+	for _, attr := range path {
+		list, ok := node[attr].([]interface{})
+		if !ok || len(list) == 0 {
+			return nil
+		}
+		if node, ok = list[0].(map[string]interface{}); !ok {
+			return nil
+		}
+	}
+	return node
+}
+
+/*
+Sets the complete set of secure properties, in clear, to the given deployment body.
+The unchanged secure properties are held hashed in the state, their clear values are read from the raw configuration.
+When removals is true, the secure properties removed from the configuration are sent with a null value to be deleted,
+it is only meant for the update of an existing deployment.
+Returns an error if the clear value of a secure property isn't available, a partial set is never sent.
+*/
+func setAppDeploymentV2BodySecureProps(d *schema.ResourceData, body *application_manager_v2.DeploymentRequestBody, removals bool) error {
+	if body.Application == nil || body.Application.Configuration == nil || body.Application.Configuration.MuleAgentApplicationPropertiesService == nil {
+		return nil
+	}
+	secure_properties, err := getAppDeploymentV2ClearSecureProps(d)
+	if err != nil {
+		return err
+	}
+	if removals {
+		old, _ := d.GetChange(APP_DEPLOYMENT_V2_SECURE_PROPS_PATH)
+		if old_props, ok := old.(map[string]interface{}); ok {
+			for k := range old_props {
+				if _, ok := secure_properties[k]; !ok {
+					secure_properties[k] = nil
+				}
+			}
+		}
+	}
+	body.Application.Configuration.MuleAgentApplicationPropertiesService.SetSecureProperties(secure_properties)
+	return nil
+}
+
+// returns the configured secure properties in clear
+func getAppDeploymentV2ClearSecureProps(d *schema.ResourceData) (map[string]interface{}, error) {
+	raw := getAppDeploymentV2RawConfigSecureProps(d)
+	configured, _ := d.Get(APP_DEPLOYMENT_V2_SECURE_PROPS_PATH).(map[string]interface{})
+	result := make(map[string]interface{}, len(configured))
+	for k, v := range configured {
+		val, _ := v.(string)
+		if !isAppDeploymentV2SecurePropHash(val) {
+			result[k] = val
+			continue
+		}
+		clear, ok := raw[k]
+		if !ok || !matchAppDeploymentV2SecurePropHash(val, clear) {
+			return nil, fmt.Errorf("the value of secure property %s is not known in clear, it must be set in the configuration", k)
+		}
+		result[k] = clear
+	}
+	return result, nil
+}
+
+// reads the secure properties from the raw configuration, only the known values are returned
+func getAppDeploymentV2RawConfigSecureProps(d *schema.ResourceData) map[string]string {
+	result := make(map[string]string)
+	val := d.GetRawConfig()
+	for _, attr := range []string{"application", "configuration", "mule_agent_app_props_service"} {
+		if !isAppDeploymentV2RawConfigObject(val) || !val.Type().HasAttribute(attr) {
+			return result
+		}
+		val = val.GetAttr(attr)
+		if val.IsNull() || !val.IsKnown() || !val.CanIterateElements() || val.LengthInt() == 0 {
+			return result
+		}
+		val = val.Index(cty.NumberIntVal(0))
+	}
+	if !isAppDeploymentV2RawConfigObject(val) || !val.Type().HasAttribute("secure_properties") {
+		return result
+	}
+	val = val.GetAttr("secure_properties")
+	if val.IsNull() || !val.IsKnown() || !val.CanIterateElements() {
+		return result
+	}
+	for k, v := range val.AsValueMap() {
+		if v.IsNull() || !v.IsKnown() || !v.Type().Equals(cty.String) {
+			continue
+		}
+		result[k] = v.AsString()
+	}
+	return result
+}
+
+func isAppDeploymentV2RawConfigObject(val cty.Value) bool {
+	return !val.IsNull() && val.IsKnown() && val.Type().IsObjectType()
+}
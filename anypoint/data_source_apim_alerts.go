@@ -0,0 +1,167 @@
+package anypoint
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceApimAlerts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceApimAlertsRead,
+		Description: `
+		Read all alerts of an API Manager instance.
+		`,
+		Schema: map[string]*schema.Schema{
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The api manager instance id.",
+			},
+			"alerts": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of alerts result of the query",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The alert id.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the alert.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of condition triggering the alert.",
+						},
+						"severity": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The severity of the alert.",
+						},
+						"enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the alert is enabled.",
+						},
+						"operator": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The operator comparing the count of events to the threshold.",
+						},
+						"threshold": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The count of events triggering the alert.",
+						},
+						"period": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The period the events are counted for, in period unit.",
+						},
+						"period_unit": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unit of the period.",
+						},
+						"policy_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the policy whose violations are counted.",
+						},
+						"response_time": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The response time in milliseconds above which a request is counted.",
+						},
+						"response_codes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The response codes counted.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"email_recipients": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The email addresses notified when the alert is triggered.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"user_recipients": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The ids of the anypoint users notified when the alert is triggered.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceApimAlertsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res []apimAlert
+	httpr, err := pco.restclient.Get(authctx, getApimAlertsPath(orgid, envid, apimid), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get alerts for api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenApimAlerts(res)
+	if err := d.Set("alerts", data); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set alerts for api " + apimid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
+
+func flattenApimAlerts(collection []apimAlert) []interface{} {
+	slice := make([]interface{}, len(collection))
+	for i, alert := range collection {
+		item := flattenApimAlert(&alert)
+		item["id"] = alert.Id
+		slice[i] = item
+	}
+	return slice
+}
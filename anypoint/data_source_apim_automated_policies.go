@@ -0,0 +1,204 @@
+package anypoint
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim_policy"
+)
+
+func dataSourceApimAutomatedPolicies() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceApimAutomatedPoliciesRead,
+		Description: `
+		Read all automated policies of an organization, optionally filtered by environment.
+		`,
+		Schema: map[string]*schema.Schema{
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The organization id where the automated policies are defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The environment id to filter the automated policies.",
+			},
+			"policies": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of automated policies result of the query",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The automated policy id.",
+						},
+						"audit": {
+							Type:        schema.TypeMap,
+							Computed:    true,
+							Description: "The automated policy's auditing data",
+						},
+						"org_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The organization id of the rule of application.",
+						},
+						"env_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The environment id of the rule of application.",
+						},
+						"configuration_data": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The policy configuration data",
+							Elem: &schema.Schema{
+								Type: schema.TypeMap,
+							},
+						},
+						"order": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The policy order.",
+						},
+						"disabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the policy is disabled.",
+						},
+						"pointcut_data": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The Method & resource conditions",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"method_regex": {
+										Type:        schema.TypeList,
+										Computed:    true,
+										Description: "The list of HTTP methods",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"uri_template_regex": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "URI template regex",
+									},
+								},
+							},
+						},
+						"asset_group_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "policy exchange asset group id.",
+						},
+						"asset_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "policy exchange asset id.",
+						},
+						"asset_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "policy exchange asset version.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceApimAutomatedPoliciesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	//perform request
+	req := pco.apimpolicyclient.DefaultApi.GetOrgAutomatedPolicies(authctx, orgid)
+	if envid != "" {
+		req = req.EnvironmentId(envid)
+	}
+	res, httpr, err := req.Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get automated policies for org " + orgid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenApimAutomatedPolicies(res.GetAutomatedPolicies())
+	if err := d.Set("policies", data); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set automated policies for org " + orgid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
+
+func flattenApimAutomatedPolicies(collection []apim_policy.AutomatedPolicy) []interface{} {
+	slice := make([]interface{}, len(collection))
+	for i, policy := range collection {
+		slice[i] = flattenApimAutomatedPolicySummary(&policy)
+	}
+	return slice
+}
+
+func flattenApimAutomatedPolicySummary(policy *apim_policy.AutomatedPolicy) map[string]interface{} {
+	result := make(map[string]interface{})
+	if val, ok := policy.GetIdOk(); ok {
+		result["id"] = strconv.Itoa(int(*val))
+	}
+	if val, ok := policy.GetAuditOk(); ok {
+		result["audit"] = flattenApimInstancePolicyAudit(val)
+	}
+	if rule, ok := policy.GetRuleOfApplicationOk(); ok {
+		result["org_id"] = rule.GetOrganizationId()
+		result["env_id"] = rule.GetEnvironmentId()
+	}
+	if val, ok := policy.GetConfigurationDataOk(); ok {
+		result["configuration_data"] = []interface{}{flattenApimInstancePolicyConfData(val)}
+	}
+	if val, ok := policy.GetOrderOk(); ok {
+		result["order"] = int(*val)
+	}
+	if val, ok := policy.GetDisabledOk(); ok {
+		result["disabled"] = *val
+	}
+	if val, ok := policy.GetPointcutDataOk(); ok {
+		result["pointcut_data"] = flattenApimInstancePolicyPointcutData(val)
+	}
+	if val, ok := policy.GetGroupIdOk(); ok {
+		result["asset_group_id"] = *val
+	}
+	if val, ok := policy.GetAssetIdOk(); ok {
+		result["asset_id"] = *val
+	}
+	if val, ok := policy.GetAssetVersionOk(); ok {
+		result["asset_version"] = *val
+	}
+	return result
+}
@@ -227,7 +227,7 @@ func flattenApimUpstreamAudit(audit *apim_upstream.Audit) map[string]interface{}
  * Returns authentication context (includes authorization header)
  */
 func getApimUpstreamAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, apim_upstream.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, apim_upstream.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, apim_upstream.ContextServerIndex, pco.server_index)
 }
 
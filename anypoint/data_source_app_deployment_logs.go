@@ -0,0 +1,395 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	application_manager_v2 "github.com/mulesoft-anypoint/anypoint-client-go/application_manager_v2"
+)
+
+// the number of log lines included in the failure diagnostics of the deployment resources
+const APP_DEPLOYMENT_V2_LOGS_TAIL_LINES = 50
+
+// statuses of a failed deployment and of a failed mule app
+const APP_DEPLOYMENT_V2_FAILED_STATUS = "FAILED"
+const APP_DEPLOYMENT_V2_APP_FAILED_STATUS = "DEPLOYMENT_FAILED"
+
+// statuses of an applied deployment and of a running mule app
+const APP_DEPLOYMENT_V2_APPLIED_STATUS = "APPLIED"
+const APP_DEPLOYMENT_V2_APP_RUNNING_STATUS = "RUNNING"
+
+// the maximum duration and polling interval of the wait for a deployment to settle after its creation or update
+const APP_DEPLOYMENT_V2_SETTLE_TIMEOUT = 10 * time.Minute
+const APP_DEPLOYMENT_V2_SETTLE_INTERVAL = 10 * time.Second
+
+var APP_DEPLOYMENT_V2_LOG_LEVELS = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+type appDeploymentLog struct {
+	Timestamp int64                   `json:"timestamp"`
+	Message   string                  `json:"message"`
+	LogLevel  string                  `json:"logLevel"`
+	Context   appDeploymentLogContext `json:"context"`
+}
+
+type appDeploymentLogContext struct {
+	Thread string `json:"thread"`
+	Logger string `json:"logger"`
+}
+
+func dataSourceAppDeploymentLogs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppDeploymentLogsRead,
+		Description: `
+		Reads the most recent log lines of a ` + "`" + `Deployment` + "`" + `.
+		This only works for Cloudhub V2 and Runtime Fabrics Apps.
+		`,
+		Schema: map[string]*schema.Schema{
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The organization where the mule app is deployed.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The environment where mule app is deployed.",
+			},
+			"deployment_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The unique id of the mule app deployment in the platform.",
+			},
+			"spec_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The deployment version (spec) to read the logs of. Defaults to the desired version of the deployment.",
+			},
+			"replica_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only reads the logs of the given replica.",
+			},
+			"levels": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Only includes log lines of the given levels. Supported values: " + strings.Join(APP_DEPLOYMENT_V2_LOG_LEVELS, ", ") + ".",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APP_DEPLOYMENT_V2_LOG_LEVELS, false)),
+				},
+			},
+			"start_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				Description:      "Only includes log lines emitted after the given time (RFC3339 format, i.e. 2024-01-30T15:04:05Z).",
+			},
+			"end_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				Description:      "Only includes log lines emitted before the given time (RFC3339 format, i.e. 2024-01-30T15:04:05Z).",
+			},
+			"max_lines": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          100,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 1000)),
+				Description:      "The maximum number of log lines to return, the most recent ones are kept.",
+			},
+			"logs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The log lines, ordered from the oldest to the most recent.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"timestamp": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time the log line was emitted (RFC3339 format).",
+						},
+						"level": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The log level.",
+						},
+						"message": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The log message.",
+						},
+						"thread": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The thread that emitted the log line.",
+						},
+						"logger": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The logger that emitted the log line.",
+						},
+					},
+				},
+			},
+			"text": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The log lines formatted as text, one line per log entry.",
+			},
+		},
+	}
+}
+
+func dataSourceAppDeploymentLogsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	deploymentid := d.Get("deployment_id").(string)
+	specid := d.Get("spec_id").(string)
+	replicaid := d.Get("replica_id").(string)
+	levels := ListInterface2ListStrings(d.Get("levels").([]interface{}))
+	max_lines := d.Get("max_lines").(int)
+	query, err := parseAppDeploymentLogsSearchOpts(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid time range for deployment " + deploymentid + " logs",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	// the spec defaults to the desired version of the deployment
+	if specid == "" {
+		authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
+		res, httpr, err := pco.appmanagerclient.DefaultApi.GetDeploymentById(authctx, orgid, envid, deploymentid).Execute()
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to get deployment for org " + orgid + " and env " + envid + " with id " + deploymentid,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		specid = res.GetDesiredVersion()
+	}
+	authctx := getRestAuthCtx(ctx, &pco)
+	logs, httpr, err := getAppDeploymentLogs(authctx, &pco, orgid, envid, deploymentid, specid, replicaid, query)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get logs of deployment " + deploymentid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	logs = filterAppDeploymentLogs(logs, levels, max_lines)
+	if err := d.Set("logs", flattenAppDeploymentLogs(logs)); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set logs of deployment " + deploymentid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.Set("text", formatAppDeploymentLogs(logs))
+	d.Set("spec_id", specid)
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
+
+func parseAppDeploymentLogsSearchOpts(d *schema.ResourceData) (url.Values, error) {
+	query := url.Values{}
+	var start, end time.Time
+	if val, ok := d.GetOk("start_time"); ok {
+		start, _ = time.Parse(time.RFC3339, val.(string))
+		query.Add("startTime", strconv.FormatInt(start.UnixMilli(), 10))
+	}
+	if val, ok := d.GetOk("end_time"); ok {
+		end, _ = time.Parse(time.RFC3339, val.(string))
+		query.Add("endTime", strconv.FormatInt(end.UnixMilli(), 10))
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end_time %s is before start_time %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	// the levels are filtered afterwards, fetching the maximum makes sure enough lines are left
+	if _, ok := d.GetOk("levels"); ok {
+		query.Add("limit", "1000")
+	} else {
+		query.Add("limit", strconv.Itoa(d.Get("max_lines").(int)))
+	}
+	query.Add("descending", "true")
+	return query, nil
+}
+
+// returns the logs of the given deployment spec (or replica) sorted from the oldest to the most recent
+func getAppDeploymentLogs(ctx context.Context, pco *ProviderConfOutput, orgid, envid, deploymentid, specid, replicaid string, query url.Values) ([]appDeploymentLog, *http.Response, error) {
+	path := fmt.Sprintf(
+		"/amc/application-manager/api/v2/organizations/%s/environments/%s/deployments/%s/specs/%s/logs",
+		url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(deploymentid), url.PathEscape(specid),
+	)
+	if replicaid != "" {
+		path = fmt.Sprintf(
+			"/amc/application-manager/api/v2/organizations/%s/environments/%s/deployments/%s/specs/%s/replicas/%s/logs",
+			url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(deploymentid), url.PathEscape(specid), url.PathEscape(replicaid),
+		)
+	}
+	logs := make([]appDeploymentLog, 0)
+	httpr, err := pco.restclient.Get(ctx, path, query, &logs)
+	if err != nil {
+		return nil, httpr, err
+	}
+	defer httpr.Body.Close()
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Timestamp < logs[j].Timestamp
+	})
+	return logs, httpr, nil
+}
+
+// keeps the log lines of the given levels, limited to the most recent max_lines
+func filterAppDeploymentLogs(logs []appDeploymentLog, levels []string, max_lines int) []appDeploymentLog {
+	result := make([]appDeploymentLog, 0, len(logs))
+	for _, log := range logs {
+		if len(levels) > 0 && !StringInSlice(levels, log.LogLevel, true) {
+			continue
+		}
+		result = append(result, log)
+	}
+	if len(result) > max_lines {
+		result = result[len(result)-max_lines:]
+	}
+	return result
+}
+
+func flattenAppDeploymentLogs(logs []appDeploymentLog) []interface{} {
+	slice := make([]interface{}, len(logs))
+	for i, log := range logs {
+		slice[i] = map[string]interface{}{
+			"timestamp": time.UnixMilli(log.Timestamp).UTC().Format(time.RFC3339Nano),
+			"level":     log.LogLevel,
+			"message":   log.Message,
+			"thread":    log.Context.Thread,
+			"logger":    log.Context.Logger,
+		}
+	}
+	return slice
+}
+
+func formatAppDeploymentLogs(logs []appDeploymentLog) string {
+	var sb strings.Builder
+	for _, log := range logs {
+		sb.WriteString(time.UnixMilli(log.Timestamp).UTC().Format(time.RFC3339Nano))
+		sb.WriteString(" " + log.LogLevel)
+		if log.Context.Thread != "" {
+			sb.WriteString(" [" + log.Context.Thread + "]")
+		}
+		if log.Context.Logger != "" {
+			sb.WriteString(" " + log.Context.Logger + ":")
+		}
+		sb.WriteString(" " + log.Message + "\n")
+	}
+	return sb.String()
+}
+
+/*
+Returns the last log lines of the given deployment formatted as text to be included in failure diagnostics.
+Returns an empty string if the logs can't be retrieved, the diagnostics of the failure itself prevail.
+*/
+func getAppDeploymentV2LogsTail(ctx context.Context, pco *ProviderConfOutput, orgid, envid, deploymentid string) string {
+	authctx := getAppDeploymentV2AuthCtx(ctx, pco)
+	res, httpr, err := pco.appmanagerclient.DefaultApi.GetDeploymentById(authctx, orgid, envid, deploymentid).Execute()
+	if err != nil {
+		return ""
+	}
+	httpr.Body.Close()
+	query := url.Values{}
+	query.Add("limit", strconv.Itoa(APP_DEPLOYMENT_V2_LOGS_TAIL_LINES))
+	query.Add("descending", "true")
+	logs, _, err := getAppDeploymentLogs(getRestAuthCtx(ctx, pco), pco, orgid, envid, deploymentid, res.GetDesiredVersion(), "", query)
+	if err != nil || len(logs) == 0 {
+		return ""
+	}
+	return "\nlast " + strconv.Itoa(len(logs)) + " log lines of " + res.GetName() + ":\n" + formatAppDeploymentLogs(logs)
+}
+
+/*
+Reads the deployment after its creation or update using the given read function.
+The deployment is first awaited until it reaches a terminal status, within APP_DEPLOYMENT_V2_SETTLE_TIMEOUT.
+A warning including the tail of the logs is added when the deployment or its mule app failed,
+it isn't reported by the read function itself to avoid fetching the logs on every refresh.
+*/
+func readAppDeploymentV2AfterChange(ctx context.Context, d *schema.ResourceData, m interface{}, read schema.ReadContextFunc) diag.Diagnostics {
+	pco := m.(ProviderConfOutput)
+	if d.Id() != "" {
+		if err := waitAppDeploymentV2Settled(ctx, &pco, d.Get("org_id").(string), d.Get("env_id").(string), d.Id()); err != nil {
+			log.Printf("[WARN] deployment %s did not reach a terminal status: %s", d.Id(), err)
+		}
+	}
+	diags := read(ctx, d, m)
+	if diags.HasError() || d.Id() == "" {
+		return diags
+	}
+	return append(diags, getAppDeploymentV2FailureDiags(ctx, &pco, d.Get("org_id").(string), d.Get("env_id").(string), d)...)
+}
+
+// returns a warning including the tail of the logs when the deployment failed
+func getAppDeploymentV2FailureDiags(ctx context.Context, pco *ProviderConfOutput, orgid, envid string, d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	status := d.Get("status").(string)
+	app_status, _ := d.Get("application.0.status").(string)
+	if status != APP_DEPLOYMENT_V2_FAILED_STATUS && app_status != APP_DEPLOYMENT_V2_APP_FAILED_STATUS {
+		return diags
+	}
+	diags = append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Deployment " + d.Get("active_name").(string) + " failed.",
+		Detail:   "The deployment " + d.Id() + " is in " + status + " status, its application is in " + app_status + " status." + getAppDeploymentV2LogsTail(ctx, pco, orgid, envid, d.Id()),
+	})
+	return diags
+}
+
+// waits for the deployment to be applied and its mule app to be running, or for either of them to fail
+func waitAppDeploymentV2Settled(ctx context.Context, pco *ProviderConfOutput, orgid, envid, id string) error {
+	authctx := getAppDeploymentV2AuthCtx(ctx, pco)
+	deadline := time.Now().Add(APP_DEPLOYMENT_V2_SETTLE_TIMEOUT)
+	for {
+		res, httpr, err := pco.appmanagerclient.DefaultApi.GetDeploymentById(authctx, orgid, envid, id).Execute()
+		if err != nil {
+			return fmt.Errorf("%s", readRestClientErrorDetails(httpr, err))
+		}
+		httpr.Body.Close()
+		if isAppDeploymentV2Settled(res) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout after %s, deployment is %s", APP_DEPLOYMENT_V2_SETTLE_TIMEOUT, res.GetStatus())
+		}
+		if err := sleepWithContext(ctx, APP_DEPLOYMENT_V2_SETTLE_INTERVAL); err != nil {
+			return err
+		}
+	}
+}
+
+func isAppDeploymentV2Settled(res *application_manager_v2.Deployment) bool {
+	application := res.GetApplication()
+	if res.GetStatus() == APP_DEPLOYMENT_V2_FAILED_STATUS || application.GetStatus() == APP_DEPLOYMENT_V2_APP_FAILED_STATUS {
+		return true
+	}
+	if res.GetStatus() != APP_DEPLOYMENT_V2_APPLIED_STATUS {
+		return false
+	}
+	// a stopped mule app doesn't reach the running status
+	return application.GetDesiredState() == "STOPPED" || application.GetStatus() == APP_DEPLOYMENT_V2_APP_RUNNING_STATUS
+}
@@ -0,0 +1,349 @@
+package anypoint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func dataSourceAppDeploymentProperties() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAppDeploymentPropertiesRead,
+		Description: `
+		Reads mule application properties and secure properties from YAML (.yaml, .yml) or Java properties (.properties) files.
+		Nested YAML keys are flattened using the dot notation (i.e. ` + "`http.listener.port`" + `) and lists of values are joined with commas.
+		The resulting maps are meant to be used as ` + "`properties`" + ` and ` + "`secure_properties`" + ` of the ` + "`mule_agent_app_props_service`" + ` block
+		in ` + "`anypoint_rtf_deployment`" + ` and ` + "`anypoint_cloudhub2_shared_space_deployment`" + ` resources.
+		Secure properties are flagged as sensitive and are therefore never displayed in the plan output.
+		`,
+		Schema: map[string]*schema.Schema{
+			"properties_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"properties_file", "secure_properties_file"},
+				Description:  "The path to the YAML or Java properties file containing the application properties.",
+			},
+			"secure_properties_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				AtLeastOneOf: []string{"properties_file", "secure_properties_file"},
+				Description:  "The path to the YAML or Java properties file containing the application secure properties.",
+			},
+			"properties": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The flattened application properties.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secure_properties": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The flattened application secure properties.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAppDeploymentPropertiesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	properties_file := d.Get("properties_file").(string)
+	secure_properties_file := d.Get("secure_properties_file").(string)
+	properties := make(map[string]string)
+	secure_properties := make(map[string]string)
+	if properties_file != "" {
+		props, err := readAppPropertiesFile(properties_file)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to read application properties file " + properties_file,
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		properties = props
+	}
+	if secure_properties_file != "" {
+		props, err := readAppPropertiesFile(secure_properties_file)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to read application secure properties file " + secure_properties_file,
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		secure_properties = props
+	}
+	if err := d.Set("properties", properties); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set application properties",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	if err := d.Set("secure_properties", secure_properties); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set application secure properties",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	// the id is derived from the files paths only, a digest of the secure properties would disclose them
+	d.SetId(CalcSha1Digest(properties_file + "|" + secure_properties_file))
+	return diags
+}
+
+// reads the given properties file, the format is selected based on the file extension
+func readAppPropertiesFile(path string) (map[string]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseAppPropertiesYaml(content)
+	case ".properties":
+		return parseAppPropertiesJava(content)
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q, expected one of .yaml, .yml or .properties", filepath.Ext(path))
+	}
+}
+
+// parses a YAML document and flattens its nested keys using the dot notation
+// the scalars are kept as written in the document, ex: 1.10 isn't turned into 1.1
+func parseAppPropertiesYaml(content []byte) (map[string]string, error) {
+	result := make(map[string]string)
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return result, nil
+	}
+	root := resolveAppPropertiesYamlAlias(doc.Content[0])
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return result, nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the YAML document root should be a mapping")
+	}
+	if err := flattenAppPropertiesYaml("", root, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func flattenAppPropertiesYaml(prefix string, node *yaml.Node, result map[string]string) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k := resolveAppPropertiesYamlAlias(node.Content[i])
+		v := resolveAppPropertiesYamlAlias(node.Content[i+1])
+		if k.Kind != yaml.ScalarNode {
+			return fmt.Errorf("unsupported non scalar key at line %d", k.Line)
+		}
+		// merge keys (<<) import the keys of the referenced mapping(s)
+		if k.Tag == "!!merge" {
+			merged := []*yaml.Node{v}
+			if v.Kind == yaml.SequenceNode {
+				merged = v.Content
+			}
+			for _, m := range merged {
+				m = resolveAppPropertiesYamlAlias(m)
+				if m.Kind != yaml.MappingNode {
+					return fmt.Errorf("unsupported merge of a non mapping value at line %d", m.Line)
+				}
+				if err := flattenAppPropertiesYaml(prefix, m, result); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + k.Value
+		}
+		switch v.Kind {
+		case yaml.MappingNode:
+			if err := flattenAppPropertiesYaml(key, v, result); err != nil {
+				return err
+			}
+		case yaml.SequenceNode:
+			items := make([]string, len(v.Content))
+			for j, item := range v.Content {
+				str, err := formatAppPropertyYamlScalar(key, resolveAppPropertiesYamlAlias(item))
+				if err != nil {
+					return err
+				}
+				items[j] = str
+			}
+			result[key] = strings.Join(items, ",")
+		default:
+			str, err := formatAppPropertyYamlScalar(key, v)
+			if err != nil {
+				return err
+			}
+			result[key] = str
+		}
+	}
+	return nil
+}
+
+// returns the text of the scalar as written in the document
+func formatAppPropertyYamlScalar(key string, node *yaml.Node) (string, error) {
+	if node.Kind != yaml.ScalarNode {
+		return "", fmt.Errorf("unsupported nested structure in list for key %s", key)
+	}
+	if node.Tag == "!!null" {
+		return "", nil
+	}
+	return node.Value, nil
+}
+
+func resolveAppPropertiesYamlAlias(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node
+}
+
+/*
+Parses the content of a Java properties file.
+Supports comments (# and !), the separators '=', ':' and whitespaces, line continuations
+and the usual escape sequences including unicode escapes.
+*/
+func parseAppPropertiesJava(content []byte) (map[string]string, error) {
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	logical := ""
+	continued := false
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if !continued && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+		if hasJavaPropertiesContinuation(line) {
+			logical += line[:len(line)-1]
+			continued = true
+			continue
+		}
+		logical += line
+		continued = false
+		key, value, err := splitJavaPropertiesLine(logical)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+		logical = ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if logical != "" {
+		key, value, err := splitJavaPropertiesLine(logical)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// a line is continued when it ends with an odd number of backslashes
+func hasJavaPropertiesContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+func splitJavaPropertiesLine(line string) (string, string, error) {
+	runes := []rune(line)
+	i := 0
+	for ; i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == '=' || runes[i] == ':' || runes[i] == ' ' || runes[i] == '\t' || runes[i] == '\f' {
+			break
+		}
+	}
+	if i > len(runes) {
+		i = len(runes)
+	}
+	rawkey := string(runes[:i])
+	rest := runes[i:]
+	// skip whitespaces, at most one '=' or ':' separator and the whitespaces following it
+	j := 0
+	for ; j < len(rest) && (rest[j] == ' ' || rest[j] == '\t' || rest[j] == '\f'); j++ {
+	}
+	if j < len(rest) && (rest[j] == '=' || rest[j] == ':') {
+		j++
+	}
+	for ; j < len(rest) && (rest[j] == ' ' || rest[j] == '\t' || rest[j] == '\f'); j++ {
+	}
+	key, err := unescapeJavaProperties(rawkey)
+	if err != nil {
+		return "", "", err
+	}
+	value, err := unescapeJavaProperties(string(rest[j:]))
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+func unescapeJavaProperties(s string) (string, error) {
+	var sb strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		i++
+		if i >= len(runes) {
+			break
+		}
+		switch runes[i] {
+		case 't':
+			sb.WriteRune('\t')
+		case 'n':
+			sb.WriteRune('\n')
+		case 'r':
+			sb.WriteRune('\r')
+		case 'f':
+			sb.WriteRune('\f')
+		case 'u':
+			if i+4 >= len(runes) {
+				return "", fmt.Errorf("malformed unicode escape sequence in %q", s)
+			}
+			code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed unicode escape sequence in %q", s)
+			}
+			sb.WriteRune(rune(code))
+			i += 4
+		default:
+			sb.WriteRune(runes[i])
+		}
+	}
+	return sb.String(), nil
+}
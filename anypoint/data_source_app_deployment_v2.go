@@ -81,6 +81,7 @@ var DeplApplicationConfigPropsReadOnlyDefinition = &schema.Resource{
 		"secure_properties": {
 			Type:        schema.TypeMap,
 			Description: "The mule application secured properties.",
+			Sensitive:   true,
 			Computed:    true,
 		},
 	},
@@ -1056,6 +1057,6 @@ func getAppDeploymentV2Attributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getAppDeploymentV2AuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, application_manager_v2.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, application_manager_v2.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, application_manager_v2.ContextServerIndex, pco.server_index)
 }
@@ -0,0 +1,425 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+type exchangeAsset struct {
+	GroupId        string                 `json:"groupId"`
+	AssetId        string                 `json:"assetId"`
+	Version        string                 `json:"version"`
+	MinorVersion   string                 `json:"minorVersion"`
+	Name           string                 `json:"name"`
+	Description    string                 `json:"description"`
+	Type           string                 `json:"type"`
+	Status         string                 `json:"status"`
+	IsPublic       bool                   `json:"isPublic"`
+	IsSnapshot     bool                   `json:"isSnapshot"`
+	OrganizationId string                 `json:"organizationId"`
+	CreatedAt      string                 `json:"createdAt"`
+	Labels         []string               `json:"labels"`
+	Files          []exchangeAssetFile    `json:"files"`
+	Versions       []exchangeAssetVersion `json:"versions"`
+}
+
+type exchangeAssetVersion struct {
+	Version   string `json:"version"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type exchangeAssetFile struct {
+	Classifier   string `json:"classifier"`
+	Packaging    string `json:"packaging"`
+	ExternalLink string `json:"externalLink"`
+	Md5          string `json:"md5"`
+	Sha1         string `json:"sha1"`
+	MainFile     string `json:"mainFile"`
+	CreatedDate  string `json:"createdDate"`
+}
+
+var EXCHANGE_ASSET_VERSION = map[string]*schema.Schema{
+	"version": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The asset version.",
+	},
+	"status": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The asset version status (published, deprecated...).",
+	},
+	"created_at": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The creation date of the asset version.",
+	},
+}
+
+var EXCHANGE_ASSET_FILE = map[string]*schema.Schema{
+	"classifier": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The file classifier (mule-application, fat-raml, oas, custom...).",
+	},
+	"packaging": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The file packaging (jar, zip, json...).",
+	},
+	"external_link": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The link to download the file.",
+	},
+	"md5": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The md5 checksum of the file.",
+	},
+	"sha1": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The sha1 checksum of the file.",
+	},
+	"main_file": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The main file of the asset inside the packaged file, if any.",
+	},
+	"created_date": {
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The creation date of the file.",
+	},
+}
+
+func dataSourceExchangeAsset() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceExchangeAssetRead,
+		Description: `
+		Query a specific asset version in exchange.
+		The version can either be given explicitly or resolved from a version constraint (i.e "~> 1.2"), in which case the latest published version satisfying the constraint is selected.
+		`,
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The asset group id in exchange.",
+			},
+			"asset_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The asset id in exchange.",
+			},
+			"version": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"version_constraint"},
+				Description:   "The asset version. If not set, the version is resolved using the version_constraint or defaults to the latest published version.",
+			},
+			"version_constraint": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validateExchangeAssetVersionConstraint),
+				Description:      "The semantic version constraint used to select the asset version (i.e \"~> 1.2\", \">= 1.0.0, < 2.0.0\").",
+			},
+			"include_snapshots": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to consider snapshot versions when resolving the version.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The asset name.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The asset description.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The asset type (rest-api, app, custom, policy, template, example...).",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the selected asset version.",
+			},
+			"organization_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The organization owning the asset.",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation date of the selected asset version.",
+			},
+			"is_public": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the asset is public.",
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The asset tags.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All the versions of the asset sorted from the most recent.",
+				Elem: &schema.Resource{
+					Schema: EXCHANGE_ASSET_VERSION,
+				},
+			},
+			"files": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The files of the selected asset version.",
+				Elem: &schema.Resource{
+					Schema: EXCHANGE_ASSET_FILE,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceExchangeAssetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	groupid := d.Get("group_id").(string)
+	assetid := d.Get("asset_id").(string)
+	ver := d.Get("version").(string)
+	constraint := d.Get("version_constraint").(string)
+	include_snapshots := d.Get("include_snapshots").(bool)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//get all versions of the asset
+	versions, httpr, err := getExchangeAssetVersions(authctx, &pco, groupid, assetid)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get exchange asset " + groupid + "/" + assetid + " versions",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	//resolve version
+	if ver == "" {
+		resolved, err := resolveExchangeAssetVersion(versions, constraint, include_snapshots)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to resolve exchange asset " + groupid + "/" + assetid + " version",
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		ver = resolved
+	}
+	//perform request
+	res, httpr, err := getExchangeAssetDetails(authctx, &pco, groupid, assetid, ver)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get exchange asset " + groupid + "/" + assetid + "/" + ver,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	//process data
+	data := flattenExchangeAsset(res)
+	data["version"] = ver
+	data["versions"] = flattenExchangeAssetVersions(versions)
+	if err := setExchangeAssetAttributesToResourceData(d, data); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set exchange asset " + groupid + "/" + assetid + "/" + ver + " attributes",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{groupid, assetid, ver}))
+	return diags
+}
+
+// returns the asset's details for a specific version
+func getExchangeAssetDetails(ctx context.Context, pco *ProviderConfOutput, groupid, assetid, ver string) (*exchangeAsset, *http.Response, error) {
+	res := &exchangeAsset{}
+	path := fmt.Sprintf("/exchange/api/v2/assets/%s/%s/%s", url.PathEscape(groupid), url.PathEscape(assetid), url.PathEscape(ver))
+	httpr, err := pco.restclient.Get(ctx, path, nil, res)
+	if err != nil {
+		return nil, httpr, err
+	}
+	defer httpr.Body.Close()
+	return res, httpr, nil
+}
+
+// returns all the versions of an asset sorted from the most recent
+func getExchangeAssetVersions(ctx context.Context, pco *ProviderConfOutput, groupid, assetid string) ([]exchangeAssetVersion, *http.Response, error) {
+	res := &exchangeAsset{}
+	path := fmt.Sprintf("/exchange/api/v2/assets/%s/%s/asset", url.PathEscape(groupid), url.PathEscape(assetid))
+	httpr, err := pco.restclient.Get(ctx, path, nil, res)
+	if err != nil {
+		return nil, httpr, err
+	}
+	defer httpr.Body.Close()
+	versions := res.Versions
+	if len(versions) == 0 && res.Version != "" {
+		versions = []exchangeAssetVersion{{Version: res.Version, Status: res.Status, CreatedAt: res.CreatedAt}}
+	}
+	sortExchangeAssetVersions(versions)
+	return versions, httpr, nil
+}
+
+/*
+Returns the most recent published version satisfying the given constraint.
+If the constraint is empty the most recent published version is returned.
+Snapshot versions are only considered when includeSnapshots is true, deprecated versions are never selected.
+*/
+func resolveExchangeAssetVersion(versions []exchangeAssetVersion, constraint string, includeSnapshots bool) (string, error) {
+	var constraints version.Constraints
+	if constraint != "" {
+		c, err := version.NewConstraint(constraint)
+		if err != nil {
+			return "", fmt.Errorf("invalid version constraint %q: %s", constraint, err)
+		}
+		constraints = c
+	}
+	var latest *version.Version
+	for _, item := range versions {
+		if strings.EqualFold(item.Status, "deprecated") {
+			continue
+		}
+		v, err := version.NewVersion(item.Version)
+		if err != nil {
+			continue
+		}
+		if v.Prerelease() != "" && !includeSnapshots {
+			continue
+		}
+		if constraints != nil && !constraints.Check(v.Core()) {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+	if latest == nil {
+		if constraint == "" {
+			return "", fmt.Errorf("no published version found")
+		}
+		return "", fmt.Errorf("no published version found satisfying the constraint %q", constraint)
+	}
+	return latest.Original(), nil
+}
+
+// sorts the versions by semantic version, most recent first. Non semantic versions are put at the end.
+func sortExchangeAssetVersions(versions []exchangeAssetVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		vi, erri := version.NewVersion(versions[i].Version)
+		vj, errj := version.NewVersion(versions[j].Version)
+		if erri != nil || errj != nil {
+			return erri == nil
+		}
+		return vi.GreaterThan(vj)
+	})
+}
+
+// validates the given value is a valid semantic version constraint
+func validateExchangeAssetVersionConstraint(i interface{}, k string) (warnings []string, errs []error) {
+	if _, err := version.NewConstraint(i.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("expected %q to be a valid version constraint, got %q: %s", k, i, err))
+	}
+	return
+}
+
+func flattenExchangeAsset(asset *exchangeAsset) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["group_id"] = asset.GroupId
+	result["asset_id"] = asset.AssetId
+	result["version"] = asset.Version
+	result["name"] = asset.Name
+	result["description"] = asset.Description
+	result["type"] = asset.Type
+	result["status"] = asset.Status
+	result["organization_id"] = asset.OrganizationId
+	result["created_at"] = asset.CreatedAt
+	result["is_public"] = asset.IsPublic
+	tags := make([]interface{}, len(asset.Labels))
+	for i, label := range asset.Labels {
+		tags[i] = label
+	}
+	result["tags"] = tags
+	result["files"] = flattenExchangeAssetFiles(asset.Files)
+	return result
+}
+
+func flattenExchangeAssetVersions(versions []exchangeAssetVersion) []interface{} {
+	slice := make([]interface{}, len(versions))
+	for i, v := range versions {
+		slice[i] = map[string]interface{}{
+			"version":    v.Version,
+			"status":     v.Status,
+			"created_at": v.CreatedAt,
+		}
+	}
+	return slice
+}
+
+func flattenExchangeAssetFiles(files []exchangeAssetFile) []interface{} {
+	slice := make([]interface{}, len(files))
+	for i, f := range files {
+		slice[i] = map[string]interface{}{
+			"classifier":    f.Classifier,
+			"packaging":     f.Packaging,
+			"external_link": f.ExternalLink,
+			"md5":           f.Md5,
+			"sha1":          f.Sha1,
+			"main_file":     f.MainFile,
+			"created_date":  f.CreatedDate,
+		}
+	}
+	return slice
+}
+
+func setExchangeAssetAttributesToResourceData(d *schema.ResourceData, data map[string]interface{}) error {
+	attributes := getExchangeAssetAttributes()
+	if data != nil {
+		for _, attr := range attributes {
+			if val, ok := data[attr]; ok {
+				if err := d.Set(attr, val); err != nil {
+					return fmt.Errorf("unable to set exchange asset attribute %s\n\tdetails: %s", attr, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func getExchangeAssetAttributes() []string {
+	attributes := [...]string{
+		"version", "name", "description", "type", "status", "organization_id",
+		"created_at", "is_public", "tags", "versions", "files",
+	}
+	return attributes[:]
+}
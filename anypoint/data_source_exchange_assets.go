@@ -0,0 +1,368 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// the size of the pages of results fetched when filtering the assets
+const EXCHANGE_ASSETS_SEARCH_PAGE_SIZE = 100
+
+func dataSourceExchangeAssets() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceExchangeAssetsRead,
+		Description: `
+		Search assets in exchange.
+		When filtering on group id, asset id or tags, all the pages of results returned by exchange are searched,
+		` + "`offset`" + ` and ` + "`limit`" + ` then apply to the filtered assets.
+		`,
+		Schema: map[string]*schema.Schema{
+			"org_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The organization id where to search assets. If not set, all the assets accessible to the user are searched.",
+			},
+			"params": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The search parameters. Should only provide one occurrence of the block.",
+				MaxItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"search": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The search text applied on the assets' name and description.",
+						},
+						"group_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Include only assets of the given group id.",
+						},
+						"asset_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Include only assets with the given asset id.",
+						},
+						"types": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Include only assets of the given types (rest-api, app, custom, policy, template, example...).",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"tags": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Include only assets having all the given tags.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"version_constraint": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validateExchangeAssetVersionConstraint),
+							Description:      "The semantic version constraint (i.e \"~> 1.2\"). The version of each asset is resolved to the latest published version satisfying the constraint, assets with no matching version are excluded.",
+						},
+						"include_snapshots": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether to include snapshot versions.",
+						},
+						"offset": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     0,
+							Description: "Skip over a number of elements by specifying an offset value for the query.",
+						},
+						"limit": {
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Default:     25,
+							Description: "Limit the number of elements in the response.",
+						},
+					},
+				},
+			},
+			"assets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The result of the query",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"group_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset group id.",
+						},
+						"asset_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset id.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset version.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset name.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset description.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset type.",
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The asset version status.",
+						},
+						"organization_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The organization owning the asset.",
+						},
+						"created_at": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The creation date of the asset version.",
+						},
+						"is_public": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the asset is public.",
+						},
+						"tags": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The asset tags.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceExchangeAssetsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	searchOpts := d.Get("params").(*schema.Set)
+	authctx := getRestAuthCtx(ctx, &pco)
+	query := parseExchangeAssetsSearchOpts(orgid, searchOpts)
+	//perform request
+	var assets []exchangeAsset
+	var err error
+	if hasExchangeAssetsFilters(searchOpts) {
+		assets, err = searchFilteredExchangeAssets(authctx, &pco, query, searchOpts)
+	} else {
+		assets, err = searchExchangeAssets(authctx, &pco, query)
+	}
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to search exchange assets",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	if constraint, include_snapshots := getExchangeAssetsVersionOpts(searchOpts); constraint != "" {
+		filtered := make([]exchangeAsset, 0)
+		for _, asset := range assets {
+			versions, httpr, err := getExchangeAssetVersions(authctx, &pco, asset.GroupId, asset.AssetId)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Unable to get exchange asset " + asset.GroupId + "/" + asset.AssetId + " versions",
+					Detail:   readRestClientErrorDetails(httpr, err),
+				})
+				return diags
+			}
+			ver, err := resolveExchangeAssetVersion(versions, constraint, include_snapshots)
+			if err != nil {
+				continue
+			}
+			for _, v := range versions {
+				if v.Version == ver {
+					asset.Version = v.Version
+					asset.Status = v.Status
+					asset.CreatedAt = v.CreatedAt
+				}
+			}
+			filtered = append(filtered, asset)
+		}
+		assets = filtered
+	}
+	//process data
+	data := flattenExchangeAssetsResult(assets)
+	if err := d.Set("assets", data); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set exchange assets",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(strconv.FormatInt(time.Now().Unix(), 10))
+	return diags
+}
+
+func parseExchangeAssetsSearchOpts(orgid string, params *schema.Set) url.Values {
+	query := url.Values{}
+	if orgid != "" {
+		query.Add("organizationIds", orgid)
+	}
+	if params.Len() == 0 {
+		return query
+	}
+	opts := params.List()[0]
+	for k, v := range opts.(map[string]interface{}) {
+		if k == "search" && v.(string) != "" {
+			query.Add("search", v.(string))
+			continue
+		}
+		if k == "types" {
+			for _, t := range v.([]interface{}) {
+				query.Add("types", t.(string))
+			}
+			continue
+		}
+		if k == "include_snapshots" {
+			query.Add("includeSnapshots", strconv.FormatBool(v.(bool)))
+			continue
+		}
+		if k == "offset" {
+			query.Add("offset", strconv.Itoa(v.(int)))
+			continue
+		}
+		if k == "limit" {
+			query.Add("limit", strconv.Itoa(v.(int)))
+			continue
+		}
+	}
+	return query
+}
+
+// returns the version constraint and whether to include snapshots from the search parameters
+func getExchangeAssetsVersionOpts(params *schema.Set) (string, bool) {
+	if params.Len() == 0 {
+		return "", false
+	}
+	opts := params.List()[0].(map[string]interface{})
+	return opts["version_constraint"].(string), opts["include_snapshots"].(bool)
+}
+
+func searchExchangeAssets(authctx context.Context, pco *ProviderConfOutput, query url.Values) ([]exchangeAsset, error) {
+	res := make([]exchangeAsset, 0)
+	httpr, err := pco.restclient.Get(authctx, "/exchange/api/v2/assets/search", query, &res)
+	if err != nil {
+		return nil, fmt.Errorf("%s", readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	return res, nil
+}
+
+/*
+Searches all the pages of results and filters the assets by group id, asset id and tags,
+as exchange doesn't support these filters. The offset and limit are applied to the filtered assets.
+*/
+func searchFilteredExchangeAssets(authctx context.Context, pco *ProviderConfOutput, query url.Values, params *schema.Set) ([]exchangeAsset, error) {
+	opts := params.List()[0].(map[string]interface{})
+	offset := opts["offset"].(int)
+	limit := opts["limit"].(int)
+	result := make([]exchangeAsset, 0)
+	query.Set("limit", strconv.Itoa(EXCHANGE_ASSETS_SEARCH_PAGE_SIZE))
+	for page := 0; ; page += EXCHANGE_ASSETS_SEARCH_PAGE_SIZE {
+		query.Set("offset", strconv.Itoa(page))
+		res, err := searchExchangeAssets(authctx, pco, query)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, filterExchangeAssets(res, params)...)
+		if len(res) < EXCHANGE_ASSETS_SEARCH_PAGE_SIZE || len(result) >= offset+limit {
+			break
+		}
+	}
+	if offset >= len(result) {
+		return make([]exchangeAsset, 0), nil
+	}
+	if offset+limit < len(result) {
+		return result[offset : offset+limit], nil
+	}
+	return result[offset:], nil
+}
+
+// returns true if the search parameters include filters applied on the results returned by exchange
+func hasExchangeAssetsFilters(params *schema.Set) bool {
+	if params.Len() == 0 {
+		return false
+	}
+	opts := params.List()[0].(map[string]interface{})
+	return opts["group_id"].(string) != "" || opts["asset_id"].(string) != "" || len(opts["tags"].([]interface{})) > 0
+}
+
+// filters the assets by group id, asset id and tags
+func filterExchangeAssets(assets []exchangeAsset, params *schema.Set) []exchangeAsset {
+	if params.Len() == 0 {
+		return assets
+	}
+	opts := params.List()[0].(map[string]interface{})
+	groupid := opts["group_id"].(string)
+	assetid := opts["asset_id"].(string)
+	tags := ListInterface2ListStrings(opts["tags"].([]interface{}))
+	result := make([]exchangeAsset, 0)
+	for _, asset := range assets {
+		if groupid != "" && asset.GroupId != groupid {
+			continue
+		}
+		if assetid != "" && asset.AssetId != assetid {
+			continue
+		}
+		if !exchangeAssetHasTags(&asset, tags) {
+			continue
+		}
+		result = append(result, asset)
+	}
+	return result
+}
+
+func exchangeAssetHasTags(asset *exchangeAsset, tags []string) bool {
+	for _, tag := range tags {
+		if !StringInSlice(asset.Labels, tag, true) {
+			return false
+		}
+	}
+	return true
+}
+
+func flattenExchangeAssetsResult(assets []exchangeAsset) []interface{} {
+	slice := make([]interface{}, len(assets))
+	for i, asset := range assets {
+		item := flattenExchangeAsset(&asset)
+		delete(item, "files")
+		slice[i] = item
+	}
+	return slice
+}
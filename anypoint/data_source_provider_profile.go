@@ -0,0 +1,58 @@
+package anypoint
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceProviderProfile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceProviderProfileRead,
+		Description: `
+		Reads the anypoint cli profile loaded by the provider, see the provider's ` + "`profile`" + ` and ` + "`credentials_file`" + ` attributes.
+		It gives access to the profile's default organization and environment.
+		`,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the profile.",
+			},
+			"organization": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The default organization of the profile, as written in the credentials file.",
+			},
+			"environment": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The default environment of the profile, as written in the credentials file.",
+			},
+			"host": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The anypoint platform host of the profile.",
+			},
+		},
+	}
+}
+
+func dataSourceProviderProfileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	if pco.profile == nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read the provider's profile",
+			Detail:   "the provider is not configured with a profile, set the provider's profile or credentials_file attributes",
+		})
+		return diags
+	}
+	d.SetId(pco.profile.Name)
+	d.Set("organization", pco.profile.Organization)
+	d.Set("environment", pco.profile.Environment)
+	d.Set("host", pco.profile.Host)
+	return diags
+}
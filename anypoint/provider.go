@@ -36,6 +36,34 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_ACCESS_TOKEN", nil),
 				Description: "the connected app's access token",
 			},
+			"jwt_private_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("ANYPOINT_JWT_PRIVATE_KEY", nil),
+				ConflictsWith: []string{"jwt_private_key_path"},
+				Description:   "the PEM encoded private key used to sign the assertions of a connected app authenticating using the jwt bearer grant, the connected app's id is set in client_id",
+			},
+			"jwt_private_key_path": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("ANYPOINT_JWT_PRIVATE_KEY_PATH", nil),
+				ConflictsWith: []string{"jwt_private_key"},
+				Description:   "the path of the PEM file containing the private key used by the jwt bearer grant",
+			},
+			"jwt_subject": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_JWT_SUBJECT", nil),
+				Description: "the subject of the jwt bearer assertions, the username of the user the connected app acts on behalf of",
+			},
+			"refresh_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_REFRESH_TOKEN", nil),
+				Description: "the refresh token exchanged for access tokens using the connected app's client_id and optional client_secret",
+			},
 			"username": {
 				Type:        schema.TypeString,
 				Deprecated:  "Remove this attribute's configuration as it no longer is used and the attribute will be removed in the next major version of the provider.",
@@ -52,10 +80,22 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_PASSWORD", nil),
 				Description: "the user's password",
 			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_PROFILE", nil),
+				Description: "the name of the anypoint cli profile to load from the credentials file, defaults to 'default' when credentials_file is set",
+			},
+			"credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_CREDENTIALS_FILE", nil),
+				Description: "the path of the anypoint cli credentials file, defaults to '~/.anypoint/credentials' when profile is set",
+			},
 			"cplane": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_CPLANE", "us"),
+				DefaultFunc: schema.EnvDefaultFunc("ANYPOINT_CPLANE", nil),
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					v := val.(string)
 					if v != "us" && v != "eu" && v != "gov" {
@@ -63,7 +103,7 @@ func Provider() *schema.Provider {
 					}
 					return
 				},
-				Description: "the anypoint control plane",
+				Description: "the anypoint control plane, defaults to the profile's host if any, 'us' otherwise",
 			},
 		},
 		ResourcesMap:         RESOURCES_MAP,
@@ -80,35 +120,101 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	client_id := d.Get("client_id").(string)
 	client_secret := d.Get("client_secret").(string)
 	access_token := d.Get("access_token").(string)
+	jwt_private_key := d.Get("jwt_private_key").(string)
+	jwt_private_key_path := d.Get("jwt_private_key_path").(string)
+	jwt_subject := d.Get("jwt_subject").(string)
+	refresh_token := d.Get("refresh_token").(string)
 	//Deprecated
 	username := d.Get("username").(string)
 	password := d.Get("password").(string)
 	cplane := d.Get("cplane").(string)
+	profile_name := d.Get("profile").(string)
+	credentials_file := d.Get("credentials_file").(string)
+
+	// the profile's settings are only used for the attributes that are neither configured nor set in env vars
+	var profile *providerProfile
+	if profile_name != "" || credentials_file != "" {
+		p, err := loadProviderProfile(credentials_file, profile_name)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to Load Anypoint CLI Profile",
+				Detail:   err.Error(),
+			})
+			return nil, diags
+		}
+		profile = p
+		// the profile's credentials are used as a pair, a client id is never combined with the secret of another connected app
+		if client_id == "" && client_secret == "" {
+			client_id = profile.ClientId
+			client_secret = profile.ClientSecret
+		}
+		if cplane == "" {
+			c, err := profile.cplane()
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Unable to Load Anypoint CLI Profile",
+					Detail:   err.Error(),
+				})
+				return nil, diags
+			}
+			cplane = c
+		}
+	}
+	if cplane == "" {
+		cplane = "us"
+	}
 
 	server_index := cplane2serverindex(cplane)
 	auth_ctx := context.WithValue(ctx, auth.ContextServerIndex, server_index)
 
 	if access_token != "" {
-		return newProviderConfOutput(access_token, server_index), diags
+		return newProviderConfOutput(access_token, server_index, profile), diags
 	}
 
 	if (username != "") && (password != "") {
 		authres, d := userPwdAuth(auth_ctx, username, password)
 		if d != nil {
-			return newProviderConfOutput("", server_index), d
+			return newProviderConfOutput("", server_index, profile), d
 		}
-		return newProviderConfOutput(authres.GetAccessToken(), server_index), diags
+		return newProviderConfOutput(authres.GetAccessToken(), server_index, profile), diags
 	}
 
-	if (client_id != "") && (client_secret != "") {
-		authres, d := connectedAppAuth(auth_ctx, client_id, client_secret)
-		if d != nil {
-			return newProviderConfOutput("", server_index), d
+	var tokensource *providerTokenSource
+	if (client_id != "") && (jwt_private_key != "" || jwt_private_key_path != "") {
+		key, err := loadJwtPrivateKey(jwt_private_key, jwt_private_key_path)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to Authenticate Using JWT Bearer",
+				Detail:   err.Error(),
+			})
+			return newProviderConfOutput("", server_index, profile), diags
+		}
+		tokensource = newJwtBearerTokenSource(server_index, client_id, key, jwt_subject)
+	} else if (client_id != "") && (refresh_token != "") {
+		tokensource = newRefreshTokenSource(server_index, client_id, client_secret, refresh_token)
+	} else if (client_id != "") && (client_secret != "") {
+		tokensource = newClientCredentialsTokenSource(server_index, client_id, client_secret)
+	}
+
+	if tokensource != nil {
+		token, err := tokensource.Token(ctx)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to Authenticate Using Connected App",
+				Detail:   err.Error(),
+			})
+			return newProviderConfOutput("", server_index, profile), diags
 		}
-		return newProviderConfOutput(authres.GetAccessToken(), server_index), diags
+		pco := newProviderConfOutput(token, server_index, profile)
+		pco.tokensource = tokensource
+		return pco, diags
 	}
 
-	return newProviderConfOutput("", server_index), diags
+	return newProviderConfOutput("", server_index, profile), diags
 
 }
 
@@ -143,37 +249,6 @@ func userPwdAuth(ctx context.Context, username string, password string) (*auth.I
 	return &authres, diags
 }
 
-/*
-Authenticates a connected app
-*/
-func connectedAppAuth(ctx context.Context, client_id string, client_secret string) (*auth.InlineResponse200, diag.Diagnostics) {
-	var diags diag.Diagnostics
-	creds := auth.NewCredentialsWithDefaults()
-	creds.SetClientId(client_id)
-	creds.SetClientSecret(client_secret)
-	//authenticate
-	cfgauth := auth.NewConfiguration()
-	authclient := auth.NewAPIClient(cfgauth)
-	authres, httpr, err := authclient.DefaultApi.ApiV2Oauth2TokenPost(ctx).Credentials(*creds).Execute()
-	if err != nil {
-		var details string
-		if httpr != nil {
-			b, _ := io.ReadAll(httpr.Body)
-			details = string(b)
-		} else {
-			details = err.Error()
-		}
-		diags := append(diags, diag.Diagnostic{
-			Severity: diag.Error,
-			Summary:  "Unable to Authenticate Using Connected App",
-			Detail:   details,
-		})
-		return auth.NewInlineResponse200(), diags
-	}
-	defer httpr.Body.Close()
-	return &authres, diags
-}
-
 /*
 returns the server index depending on the control plane name
 if the control plane is not recognized, returns -1
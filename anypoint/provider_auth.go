@@ -0,0 +1,230 @@
+package anypoint
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const PROVIDER_TOKEN_PATH = "/accounts/api/v2/oauth2/token"
+
+// the access token is renewed when it expires within this margin
+const PROVIDER_TOKEN_EXPIRY_MARGIN = 5 * time.Minute
+
+// the validity of the assertions signed for the jwt bearer grant
+const PROVIDER_JWT_ASSERTION_VALIDITY = 5 * time.Minute
+
+const PROVIDER_JWT_BEARER_GRANT_TYPE = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+type providerTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+/*
+providerTokenSource fetches the provider's access token using an oauth2 grant and renews it before it expires.
+It is shared by the client credentials, jwt bearer and refresh token flows, only the parameters of the grant differ.
+*/
+type providerTokenSource struct {
+	mu            sync.Mutex
+	restclient    *RestClient
+	server_index  int
+	grant         func(ts *providerTokenSource) (url.Values, error)
+	access_token  string
+	refresh_token string
+	expiry        time.Time
+}
+
+// creates a token source using the connected app's client credentials
+func newClientCredentialsTokenSource(server_index int, client_id, client_secret string) *providerTokenSource {
+	return &providerTokenSource{
+		restclient:   NewRestClient(),
+		server_index: server_index,
+		grant: func(ts *providerTokenSource) (url.Values, error) {
+			return url.Values{
+				"grant_type":    {"client_credentials"},
+				"client_id":     {client_id},
+				"client_secret": {client_secret},
+			}, nil
+		},
+	}
+}
+
+// creates a token source signing a new assertion with the connected app's private key for every renewal
+func newJwtBearerTokenSource(server_index int, client_id string, key *rsa.PrivateKey, subject string) *providerTokenSource {
+	return &providerTokenSource{
+		restclient:   NewRestClient(),
+		server_index: server_index,
+		grant: func(ts *providerTokenSource) (url.Values, error) {
+			assertion, err := newJwtBearerAssertion(key, client_id, subject, ts.tokenURL(), time.Now())
+			if err != nil {
+				return nil, err
+			}
+			return url.Values{
+				"grant_type": {PROVIDER_JWT_BEARER_GRANT_TYPE},
+				"client_id":  {client_id},
+				"assertion":  {assertion},
+			}, nil
+		},
+	}
+}
+
+// creates a token source exchanging the given refresh token, the refresh token is replaced when the platform rotates it
+func newRefreshTokenSource(server_index int, client_id, client_secret, refresh_token string) *providerTokenSource {
+	return &providerTokenSource{
+		restclient:    NewRestClient(),
+		server_index:  server_index,
+		refresh_token: refresh_token,
+		grant: func(ts *providerTokenSource) (url.Values, error) {
+			values := url.Values{
+				"grant_type":    {"refresh_token"},
+				"client_id":     {client_id},
+				"refresh_token": {ts.refresh_token},
+			}
+			if client_secret != "" {
+				values.Set("client_secret", client_secret)
+			}
+			return values, nil
+		},
+	}
+}
+
+// returns a valid access token, a new one is requested if the current one is missing or about to expire
+func (ts *providerTokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.access_token != "" && (ts.expiry.IsZero() || time.Now().Add(PROVIDER_TOKEN_EXPIRY_MARGIN).Before(ts.expiry)) {
+		return ts.access_token, nil
+	}
+	body, err := ts.grant(ts)
+	if err != nil {
+		return "", err
+	}
+	authctx := context.WithValue(ctx, RestContextServerIndex, ts.server_index)
+	var res providerTokenResponse
+	httpr, err := ts.restclient.Post(authctx, PROVIDER_TOKEN_PATH, body, &res)
+	if err != nil {
+		return "", fmt.Errorf("%s", readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	if res.AccessToken == "" {
+		return "", fmt.Errorf("the token response doesn't contain any access token")
+	}
+	ts.access_token = res.AccessToken
+	if res.RefreshToken != "" {
+		ts.refresh_token = res.RefreshToken
+	}
+	ts.expiry = time.Time{}
+	if res.ExpiresIn > 0 {
+		ts.expiry = time.Now().Add(time.Duration(res.ExpiresIn) * time.Second)
+	}
+	return ts.access_token, nil
+}
+
+// returns the url of the token endpoint of the selected control plane, used as audience of the jwt assertions
+func (ts *providerTokenSource) tokenURL() string {
+	base := REST_CLIENT_SERVERS[0]
+	if ts.server_index >= 0 && ts.server_index < len(REST_CLIENT_SERVERS) {
+		base = REST_CLIENT_SERVERS[ts.server_index]
+	}
+	return base + PROVIDER_TOKEN_PATH
+}
+
+/*
+Returns the provider's access token.
+When the provider authenticates using a token source, the token is renewed before it expires,
+the last known token is returned if the renewal fails so the error is reported by the request itself.
+*/
+func (pco *ProviderConfOutput) getAccessToken(ctx context.Context) string {
+	if pco.tokensource == nil {
+		return pco.access_token
+	}
+	token, err := pco.tokensource.Token(ctx)
+	if err != nil {
+		log.Printf("[WARN] unable to renew the provider's access token: %s", err)
+		return pco.access_token
+	}
+	return token
+}
+
+// signs an RS256 assertion for the jwt bearer grant
+func newJwtBearerAssertion(key *rsa.PrivateKey, client_id, subject, audience string, now time.Time) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	header := map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+	}
+	claims := map[string]interface{}{
+		"iss": client_id,
+		"sub": subject,
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": now.Add(PROVIDER_JWT_ASSERTION_VALIDITY).Unix(),
+		"jti": hex.EncodeToString(jti),
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// reads the jwt bearer private key from the given PEM content, or from the given file path if the content is empty
+func loadJwtPrivateKey(content, path string) (*rsa.PrivateKey, error) {
+	if content == "" && path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read private key file %s: %s", path, err)
+		}
+		content = string(b)
+	}
+	return parseJwtPrivateKey(content)
+}
+
+// parses an RSA private key encoded in PKCS#1 or PKCS#8 PEM format
+func parseJwtPrivateKey(content string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(content))
+	if block == nil {
+		return nil, fmt.Errorf("the private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the private key: %s", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("the private key is not an RSA key")
+	}
+	return key, nil
+}
@@ -35,6 +35,8 @@ import (
 
 type ProviderConfOutput struct {
 	access_token            string
+	tokensource             *providerTokenSource
+	profile                 *providerProfile
 	server_index            int
 	vpcclient               *vpc.APIClient
 	vpnclient               *vpn.APIClient
@@ -66,9 +68,10 @@ type ProviderConfOutput struct {
 	sgcrldistribcfgsclient  *secretgroup_crl_distributor_configs.APIClient
 	rtfclient               *rtf.APIClient
 	appmanagerclient        *application_manager_v2.APIClient
+	restclient              *RestClient
 }
 
-func newProviderConfOutput(access_token string, server_index int) ProviderConfOutput {
+func newProviderConfOutput(access_token string, server_index int, profile *providerProfile) ProviderConfOutput {
 	//preparing clients
 	vpccfg := vpc.NewConfiguration()
 	vpncfg := vpn.NewConfiguration()
@@ -131,10 +134,12 @@ func newProviderConfOutput(access_token string, server_index int) ProviderConfOu
 	sgcrldistribcfgsclient := secretgroup_crl_distributor_configs.NewAPIClient(sgcrldistribcfgs_cfg)
 	rtfclient := rtf.NewAPIClient(rtf_cfg)
 	appmanagerclient := application_manager_v2.NewAPIClient(appmanager_cfg)
+	restclient := NewRestClient()
 
 	return ProviderConfOutput{
 		access_token:            access_token,
 		server_index:            server_index,
+		profile:                 profile,
 		vpcclient:               vpcclient,
 		vpnclient:               vpnclient,
 		orgclient:               orgclient,
@@ -165,5 +170,6 @@ func newProviderConfOutput(access_token string, server_index int) ProviderConfOu
 		sgcrldistribcfgsclient:  sgcrldistribcfgsclient,
 		rtfclient:               rtfclient,
 		appmanagerclient:        appmanagerclient,
+		restclient:              restclient,
 	}
 }
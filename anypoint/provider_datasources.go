@@ -32,6 +32,8 @@ var DATASOURCES_MAP = map[string]*schema.Resource{
 	"anypoint_apim_instance":                         dataSourceApimInstance(),
 	"anypoint_apim_instance_policy":                  dataSourceApimInstancePolicy(),
 	"anypoint_apim_instance_policies":                dataSourceApimInstancePolicies(),
+	"anypoint_apim_automated_policies":               dataSourceApimAutomatedPolicies(),
+	"anypoint_apim_alerts":                           dataSourceApimAlerts(),
 	"anypoint_apim_instance_upstreams":               dataSourceApimInstanceUpstreams(),
 	"anypoint_flexgateway_target":                    dataSourceFlexGatewayTarget(),
 	"anypoint_flexgateway_targets":                   dataSourceFlexGatewayTargets(),
@@ -52,11 +54,16 @@ var DATASOURCES_MAP = map[string]*schema.Resource{
 	"anypoint_secretgroup_crldistrib_cfgs":           dataSourceSecretGroupCrlDistribCfgs(),
 	"anypoint_exchange_policy_templates":             dataSourceExchangePolicyTemplates(),
 	"anypoint_exchange_policy_template":              dataSourceExchangePolicyTemplate(),
+	"anypoint_exchange_asset":                        dataSourceExchangeAsset(),
+	"anypoint_exchange_assets":                       dataSourceExchangeAssets(),
 	"anypoint_fabrics_list":                          dataSourceFabricsCollection(),
 	"anypoint_fabrics":                               dataSourceFabrics(),
 	"anypoint_fabrics_associations":                  dataSourceFabricsAssociations(),
 	"anypoint_fabrics_helm_repo":                     dataSourceFabricsHelmRepoProps(),
 	"anypoint_fabrics_health":                        dataSourceFabricsHealth(),
 	"anypoint_app_deployment_v2":                     dataSourceAppDeploymentV2(),
+	"anypoint_app_deployment_properties":             dataSourceAppDeploymentProperties(),
+	"anypoint_app_deployment_logs":                   dataSourceAppDeploymentLogs(),
 	"anypoint_app_deployments_v2":                    dataSourceAppDeploymentsV2(),
+	"anypoint_provider_profile":                      dataSourceProviderProfile(),
 }
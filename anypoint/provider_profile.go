@@ -0,0 +1,92 @@
+package anypoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// the location of the anypoint cli credentials file, relative to the user's home directory
+const PROVIDER_DEFAULT_CREDENTIALS_FILE = ".anypoint/credentials"
+
+const PROVIDER_DEFAULT_PROFILE = "default"
+
+/*
+providerProfile is a named profile of the anypoint cli credentials file.
+The file is a JSON object indexed by profile name, ex:
+
+	{
+	  "default": {
+	    "client_id": "...",
+	    "client_secret": "...",
+	    "organization": "...",
+	    "environment": "...",
+	    "host": "eu1.anypoint.mulesoft.com"
+	  }
+	}
+*/
+type providerProfile struct {
+	Name         string `json:"-"`
+	ClientId     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	Organization string `json:"organization"`
+	Environment  string `json:"environment"`
+	Host         string `json:"host"`
+}
+
+// loads the given profile from the credentials file, the cli's default file and profile are used when empty
+func loadProviderProfile(path, name string) (*providerProfile, error) {
+	if name == "" {
+		name = PROVIDER_DEFAULT_PROFILE
+	}
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to locate the credentials file: %s", err)
+		}
+		path = filepath.Join(home, PROVIDER_DEFAULT_CREDENTIALS_FILE)
+	} else if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("unable to locate the credentials file: %s", err)
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the credentials file %s: %s", path, err)
+	}
+	var profiles map[string]*providerProfile
+	if err := json.Unmarshal(b, &profiles); err != nil {
+		return nil, fmt.Errorf("unable to parse the credentials file %s: %s", path, err)
+	}
+	profile, ok := profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("profile %s not found in the credentials file %s", name, path)
+	}
+	profile.Name = name
+	return profile, nil
+}
+
+/*
+returns the control plane name of the profile's host
+returns an empty string if the profile has no host and an error if the host is not recognized
+*/
+func (p *providerProfile) cplane() (string, error) {
+	host := strings.ToLower(strings.TrimSpace(p.Host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	switch host {
+	case "":
+		return "", nil
+	case "anypoint.mulesoft.com":
+		return "us", nil
+	case "eu1.anypoint.mulesoft.com":
+		return "eu", nil
+	case "gov.anypoint.mulesoft.com":
+		return "gov", nil
+	}
+	return "", fmt.Errorf("unrecognized host %s in profile %s, set the cplane explicitly", p.Host, p.Name)
+}
@@ -3,43 +3,67 @@ package anypoint
 import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 var RESOURCES_MAP = map[string]*schema.Resource{
-	"anypoint_vpc":                                   resourceVPC(),
-	"anypoint_vpn":                                   resourceVPN(),
-	"anypoint_bg":                                    resourceBG(),
-	"anypoint_rolegroup_roles":                       resourceRoleGroupRoles(),
-	"anypoint_rolegroup":                             resourceRoleGroup(),
-	"anypoint_env":                                   resourceENV(),
-	"anypoint_user":                                  resourceUser(),
-	"anypoint_user_rolegroup":                        resourceUserRolegroup(),
-	"anypoint_team":                                  resourceTeam(),
-	"anypoint_team_roles":                            resourceTeamRoles(),
-	"anypoint_team_member":                           resourceTeamMember(),
-	"anypoint_team_group_mappings":                   resourceTeamGroupMappings(),
-	"anypoint_dlb":                                   resourceDLB(),
-	"anypoint_idp_oidc":                              resourceOIDC(),
-	"anypoint_idp_saml":                              resourceSAML(),
-	"anypoint_connected_app":                         resourceConnectedApp(),
-	"anypoint_amq":                                   resourceAMQ(),
-	"anypoint_ame":                                   resourceAME(),
-	"anypoint_ame_binding":                           resourceAMEBinding(),
-	"anypoint_apim_flexgateway":                      resourceApimFlexGateway(),
-	"anypoint_apim_mule4":                            resourceApimMule4(),
-	"anypoint_apim_policy_client_id_enforcement":     resourceApimInstancePolicyClientIdEnf(),
-	"anypoint_apim_policy_jwt_validation":            resourceApimInstancePolicyJwtValidation(),
-	"anypoint_apim_policy_rate_limiting":             resourceApimInstancePolicyRateLimiting(),
-	"anypoint_apim_policy_basic_auth":                resourceApimInstancePolicyBasicAuth(),
-	"anypoint_apim_policy_message_logging":           resourceApimInstancePolicyMessageLogging(),
-	"anypoint_apim_policy_custom":                    resourceApimInstancePolicyCustom(),
-	"anypoint_secretgroup":                           resourceSecretGroup(),
-	"anypoint_secretgroup_keystore":                  resourceSecretGroupKeystore(),
-	"anypoint_secretgroup_truststore":                resourceSecretGroupTruststore(),
-	"anypoint_secretgroup_certificate":               resourceSecretGroupCertificate(),
-	"anypoint_secretgroup_tlscontext_flexgateway":    resourceSecretGroupTlsContextFG(),
-	"anypoint_secretgroup_tlscontext_mule":           resourceSecretGroupTlsContextMule(),
-	"anypoint_secretgroup_tlscontext_securityfabric": resourceSecretGroupTlsContextSF(),
-	"anypoint_secretgroup_crldistrib_cfgs":           resourceSecretGroupCrlDistribCfgs(),
-	"anypoint_fabrics":                               resourceFabrics(),
-	"anypoint_fabrics_associations":                  resourceFabricsAssociations(),
-	"anypoint_cloudhub2_shared_space_deployment":     resourceCloudhub2SharedSpaceDeployment(),
-	"anypoint_rtf_deployment":                        resourceRTFDeployment(),
+	"anypoint_vpc":                                    resourceVPC(),
+	"anypoint_vpn":                                    resourceVPN(),
+	"anypoint_bg":                                     resourceBG(),
+	"anypoint_rolegroup_roles":                        resourceRoleGroupRoles(),
+	"anypoint_rolegroup":                              resourceRoleGroup(),
+	"anypoint_env":                                    resourceENV(),
+	"anypoint_user":                                   resourceUser(),
+	"anypoint_user_rolegroup":                         resourceUserRolegroup(),
+	"anypoint_team":                                   resourceTeam(),
+	"anypoint_team_roles":                             resourceTeamRoles(),
+	"anypoint_team_member":                            resourceTeamMember(),
+	"anypoint_team_group_mappings":                    resourceTeamGroupMappings(),
+	"anypoint_dlb":                                    resourceDLB(),
+	"anypoint_idp_oidc":                               resourceOIDC(),
+	"anypoint_idp_saml":                               resourceSAML(),
+	"anypoint_connected_app":                          resourceConnectedApp(),
+	"anypoint_amq":                                    resourceAMQ(),
+	"anypoint_ame":                                    resourceAME(),
+	"anypoint_ame_binding":                            resourceAMEBinding(),
+	"anypoint_apim_flexgateway":                       resourceApimFlexGateway(),
+	"anypoint_flexgateway_registration":               resourceFlexGatewayRegistration(),
+	"anypoint_apim_mule4":                             resourceApimMule4(),
+	"anypoint_apim_policy_client_id_enforcement":      resourceApimInstancePolicyClientIdEnf(),
+	"anypoint_apim_policy_jwt_validation":             resourceApimInstancePolicyJwtValidation(),
+	"anypoint_apim_policy_rate_limiting":              resourceApimInstancePolicyRateLimiting(),
+	"anypoint_apim_policy_basic_auth":                 resourceApimInstancePolicyBasicAuth(),
+	"anypoint_apim_policy_message_logging":            resourceApimInstancePolicyMessageLogging(),
+	"anypoint_apim_policy_custom":                     resourceApimInstancePolicyCustom(),
+	"anypoint_apim_policy_ip_allowlist":               resourceApimInstancePolicyIpAllowlist(),
+	"anypoint_apim_policy_ip_blocklist":               resourceApimInstancePolicyIpBlocklist(),
+	"anypoint_apim_policy_cors":                       resourceApimInstancePolicyCors(),
+	"anypoint_apim_policy_header_injection":           resourceApimInstancePolicyHeaderInjection(),
+	"anypoint_apim_policy_header_removal":             resourceApimInstancePolicyHeaderRemoval(),
+	"anypoint_apim_policy_spike_control":              resourceApimInstancePolicySpikeControl(),
+	"anypoint_apim_policy_rate_limiting_sla_based":    resourceApimInstancePolicyRateLimitingSla(),
+	"anypoint_apim_policy_http_caching":               resourceApimInstancePolicyHttpCaching(),
+	"anypoint_apim_policy_json_threat_protection":     resourceApimInstancePolicyJsonThreatProtection(),
+	"anypoint_apim_policy_xml_threat_protection":      resourceApimInstancePolicyXmlThreatProtection(),
+	"anypoint_apim_policy_oauth2_token_introspection": resourceApimInstancePolicyOAuth2TokenIntrospection(),
+	"anypoint_apim_policies_order":                    resourceApimPoliciesOrder(),
+	"anypoint_apim_automated_policy":                  resourceApimAutomatedPolicy(),
+	"anypoint_apim_promotion":                         resourceApimPromotion(),
+	"anypoint_apim_alert":                             resourceApimAlert(),
+	"anypoint_apim_api_group":                         resourceApimApiGroup(),
+	"anypoint_apim_api_group_instance":                resourceApimApiGroupInstance(),
+	"anypoint_apim_sla_tier":                          resourceApimSlaTier(),
+	"anypoint_apim_contract":                          resourceApimContract(),
+	"anypoint_exchange_client_application":            resourceExchangeClientApplication(),
+	"anypoint_secretgroup":                            resourceSecretGroup(),
+	"anypoint_secretgroup_keystore":                   resourceSecretGroupKeystore(),
+	"anypoint_secretgroup_truststore":                 resourceSecretGroupTruststore(),
+	"anypoint_secretgroup_certificate":                resourceSecretGroupCertificate(),
+	"anypoint_secretgroup_tlscontext_flexgateway":     resourceSecretGroupTlsContextFG(),
+	"anypoint_secretgroup_tlscontext_mule":            resourceSecretGroupTlsContextMule(),
+	"anypoint_secretgroup_tlscontext_securityfabric":  resourceSecretGroupTlsContextSF(),
+	"anypoint_secretgroup_crldistrib_cfgs":            resourceSecretGroupCrlDistribCfgs(),
+	"anypoint_fabrics":                                resourceFabrics(),
+	"anypoint_fabrics_associations":                   resourceFabricsAssociations(),
+	"anypoint_fabrics_association":                    resourceFabricsAssociation(),
+	"anypoint_fabrics_ingress":                        resourceFabricsIngress(),
+	"anypoint_fabrics_log_forwarding":                 resourceFabricsLogForwarding(),
+	"anypoint_cloudhub2_shared_space_deployment":      resourceCloudhub2SharedSpaceDeployment(),
+	"anypoint_rtf_deployment":                         resourceRTFDeployment(),
 }
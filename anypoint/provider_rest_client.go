@@ -0,0 +1,187 @@
+package anypoint
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type restContextKey string
+
+var (
+	// RestContextAccessToken takes a string oauth2 access token as the authentication for the request.
+	RestContextAccessToken = restContextKey("accesstoken")
+	// RestContextServerIndex uses a server configuration from the index.
+	RestContextServerIndex = restContextKey("serverIndex")
+)
+
+// the anypoint control planes base urls, indexed in the same order as the generated client libraries
+var REST_CLIENT_SERVERS = []string{
+	"https://anypoint.mulesoft.com",
+	"https://eu1.anypoint.mulesoft.com",
+	"https://gov.anypoint.mulesoft.com",
+}
+
+/*
+RestClient is a minimal JSON client used to reach the anypoint platform endpoints
+that are not (yet) covered by an anypoint client library module.
+It follows the same conventions as the generated clients: the access token and server index
+are taken from the context and the http response is always returned with a readable body.
+*/
+type RestClient struct {
+	HTTPClient *http.Client
+	UserAgent  string
+	Servers    []string
+}
+
+// RestClientError provides access to the body and the status of the returned errors.
+type RestClientError struct {
+	body  []byte
+	error string
+}
+
+// Error returns non-empty string if there was an error.
+func (e RestClientError) Error() string {
+	return e.error
+}
+
+// Body returns the raw bytes of the response
+func (e RestClientError) Body() []byte {
+	return e.body
+}
+
+func NewRestClient() *RestClient {
+	return &RestClient{
+		HTTPClient: http.DefaultClient,
+		UserAgent:  "terraform-provider-anypoint",
+		Servers:    REST_CLIENT_SERVERS,
+	}
+}
+
+func (c *RestClient) Get(ctx context.Context, path string, query url.Values, result interface{}) (*http.Response, error) {
+	return c.Do(ctx, http.MethodGet, path, query, nil, result)
+}
+
+func (c *RestClient) Post(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.Do(ctx, http.MethodPost, path, nil, body, result)
+}
+
+func (c *RestClient) Put(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.Do(ctx, http.MethodPut, path, nil, body, result)
+}
+
+func (c *RestClient) Patch(ctx context.Context, path string, body interface{}, result interface{}) (*http.Response, error) {
+	return c.Do(ctx, http.MethodPatch, path, nil, body, result)
+}
+
+func (c *RestClient) Delete(ctx context.Context, path string) (*http.Response, error) {
+	return c.Do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+/*
+Performs a request on the given path of the control plane selected in the context.
+The body is encoded as a form if it is of type url.Values, as JSON otherwise.
+The result is decoded from JSON unless it is a *string in which case the raw body is returned.
+*/
+func (c *RestClient) Do(ctx context.Context, method string, path string, query url.Values, body interface{}, result interface{}) (*http.Response, error) {
+	base, err := c.serverURL(ctx)
+	if err != nil {
+		return nil, &RestClientError{error: err.Error()}
+	}
+	u, err := url.Parse(base + path)
+	if err != nil {
+		return nil, &RestClientError{error: err.Error()}
+	}
+	if len(query) > 0 {
+		q := u.Query()
+		for k, vals := range query {
+			for _, v := range vals {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	var reqBody io.Reader
+	contentType := ""
+	if body != nil {
+		switch b := body.(type) {
+		case url.Values:
+			reqBody = strings.NewReader(b.Encode())
+			contentType = "application/x-www-form-urlencoded"
+		default:
+			buf, err := json.Marshal(b)
+			if err != nil {
+				return nil, &RestClientError{error: err.Error()}
+			}
+			reqBody = bytes.NewBuffer(buf)
+			contentType = "application/json"
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, &RestClientError{error: err.Error()}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.UserAgent)
+	if token, ok := ctx.Value(RestContextAccessToken).(string); ok && token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpr, err := c.HTTPClient.Do(req)
+	if err != nil || httpr == nil {
+		return httpr, err
+	}
+	resBody, err := io.ReadAll(httpr.Body)
+	httpr.Body.Close()
+	httpr.Body = io.NopCloser(bytes.NewBuffer(resBody))
+	if err != nil {
+		return httpr, err
+	}
+	if httpr.StatusCode >= 300 {
+		return httpr, &RestClientError{body: resBody, error: httpr.Status}
+	}
+	if result == nil || len(resBody) == 0 {
+		return httpr, nil
+	}
+	if s, ok := result.(*string); ok {
+		*s = string(resBody)
+		return httpr, nil
+	}
+	if err := json.Unmarshal(resBody, result); err != nil {
+		return httpr, &RestClientError{body: resBody, error: err.Error()}
+	}
+	return httpr, nil
+}
+
+func (c *RestClient) serverURL(ctx context.Context) (string, error) {
+	index := 0
+	if val, ok := ctx.Value(RestContextServerIndex).(int); ok {
+		index = val
+	}
+	if index < 0 || index >= len(c.Servers) {
+		return "", fmt.Errorf("index %v out of range %v", index, len(c.Servers)-1)
+	}
+	return c.Servers[index], nil
+}
+
+func getRestAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
+	tmp := context.WithValue(ctx, RestContextAccessToken, pco.getAccessToken(ctx))
+	return context.WithValue(tmp, RestContextServerIndex, pco.server_index)
+}
+
+// reads the details of an error returned by the rest client
+func readRestClientErrorDetails(httpr *http.Response, err error) string {
+	if httpr != nil && httpr.StatusCode >= 400 {
+		defer httpr.Body.Close()
+		b, _ := io.ReadAll(httpr.Body)
+		return string(b)
+	}
+	return err.Error()
+}
@@ -267,6 +267,6 @@ func getAMEPatchWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getAMEAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, ame.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, ame.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, ame.ContextServerIndex, pco.server_index)
 }
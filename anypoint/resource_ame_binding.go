@@ -642,6 +642,6 @@ func getAMEBindingRulesWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getAMEBindingAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, ame_binding.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, ame_binding.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, ame_binding.ContextServerIndex, pco.server_index)
 }
@@ -336,6 +336,6 @@ func getAMQPatchWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getAMQAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, amq.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, amq.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, amq.ContextServerIndex, pco.server_index)
 }
@@ -0,0 +1,430 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	APIM_ALERT_TYPE_POLICY_VIOLATION = "policy-violation"
+	APIM_ALERT_TYPE_RESPONSE_TIME    = "response-time"
+	APIM_ALERT_TYPE_RESPONSE_CODE    = "response-code"
+	APIM_ALERT_TYPE_REQUEST_COUNT    = "request-count"
+)
+
+var APIM_ALERT_TYPES = []string{
+	APIM_ALERT_TYPE_POLICY_VIOLATION, APIM_ALERT_TYPE_RESPONSE_TIME,
+	APIM_ALERT_TYPE_RESPONSE_CODE, APIM_ALERT_TYPE_REQUEST_COUNT,
+}
+
+var APIM_ALERT_SEVERITIES = []string{"CRITICAL", "WARNING", "INFO"}
+
+var APIM_ALERT_OPERATORS = []string{"GREATER_THAN", "GREATER_THAN_OR_EQUAL", "LESS_THAN", "LESS_THAN_OR_EQUAL"}
+
+var APIM_ALERT_PERIOD_UNITS = []string{"MINUTES", "HOURS"}
+
+type apimAlert struct {
+	Id         string               `json:"id,omitempty"`
+	Name       string               `json:"name"`
+	Type       string               `json:"type"`
+	Severity   string               `json:"severity"`
+	Enabled    bool                 `json:"enabled"`
+	Condition  apimAlertCondition   `json:"condition"`
+	Recipients []apimAlertRecipient `json:"recipients"`
+}
+
+type apimAlertCondition struct {
+	Operator      string   `json:"operator"`
+	Threshold     int      `json:"threshold"`
+	Period        int      `json:"period"`
+	PeriodUnit    string   `json:"periodUnit"`
+	ResponseCodes []string `json:"responseCodes,omitempty"`
+	ResponseTime  int      `json:"responseTime,omitempty"`
+	PolicyId      string   `json:"policyId,omitempty"`
+}
+
+type apimAlertRecipient struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+func resourceApimAlert() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimAlertCreate,
+		ReadContext:   resourceApimAlertRead,
+		UpdateContext: resourceApimAlertUpdate,
+		DeleteContext: resourceApimAlertDelete,
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			return validateApimAlertCondition(rd)
+		},
+		Description: `
+		Creates an alert on an API Manager instance.
+		The alert is triggered when the condition is met during the given period and notifies the email and user recipients.
+		Supported alert types are:
+		  * ` + "`policy-violation`" + `: the number of violations of the policy ` + "`policy_id`" + `.
+		  * ` + "`response-time`" + `: the number of requests slower than ` + "`response_time`" + ` milliseconds.
+		  * ` + "`response-code`" + `: the number of responses having one of the ` + "`response_codes`" + `.
+		  * ` + "`request-count`" + `: the number of requests.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{apim_id}/{alert_id}",
+			},
+			"alert_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The alert id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api manager instance id the alert is attached to.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the alert.",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the alert is enabled.",
+			},
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APIM_ALERT_TYPES, false)),
+				Description: `
+				The type of condition triggering the alert.
+				Supported values are ` + "`policy-violation`, `response-time`, `response-code` and `request-count`" + `.
+				`,
+			},
+			"severity": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APIM_ALERT_SEVERITIES, false)),
+				Description:      "The severity of the alert. Supported values are `CRITICAL`, `WARNING` and `INFO`.",
+			},
+			"operator": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "GREATER_THAN",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APIM_ALERT_OPERATORS, false)),
+				Description:      "The operator comparing the count of events to the threshold. Supported values are `GREATER_THAN`, `GREATER_THAN_OR_EQUAL`, `LESS_THAN` and `LESS_THAN_OR_EQUAL`.",
+			},
+			"threshold": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+				Description:      "The count of events triggering the alert.",
+			},
+			"period": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The period the events are counted for, in period unit.",
+			},
+			"period_unit": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "MINUTES",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APIM_ALERT_PERIOD_UNITS, false)),
+				Description:      "The unit of the period. Supported values are `MINUTES` and `HOURS`.",
+			},
+			"policy_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The id of the policy whose violations are counted. Only for `policy-violation` alerts.",
+			},
+			"response_time": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The response time in milliseconds above which a request is counted. Only for `response-time` alerts.",
+			},
+			"response_codes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The response codes counted, ex: 500. Only for `response-code` alerts.",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringMatch(regexp.MustCompile(`^[1-5][0-9]{2}$`), "must be an http status code")),
+				},
+			},
+			"email_recipients": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The email addresses notified when the alert is triggered.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"user_recipients": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The ids of the anypoint users notified when the alert is triggered.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "env_id", "apim_id", "alert_id"}),
+		},
+	}
+}
+
+func resourceApimAlertCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newApimAlertBody(d)
+	var res apimAlert
+	httpr, err := pco.restclient.Post(authctx, getApimAlertsPath(orgid, envid, apimid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create alert " + body.Name + " for api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, res.Id}))
+	return resourceApimAlertRead(ctx, d, m)
+}
+
+func resourceApimAlertRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimAlertId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res apimAlert
+	httpr, err := pco.restclient.Get(authctx, getApimAlertPath(orgid, envid, apimid, id), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read alert " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenApimAlert(&res)
+	for _, attr := range getApimAlertAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set alert " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, id}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("apim_id", apimid)
+	d.Set("alert_id", id)
+	return diags
+}
+
+func resourceApimAlertUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimAlertId(d)
+	if d.HasChanges(getApimAlertUpdatableAttributes()...) {
+		authctx := getRestAuthCtx(ctx, &pco)
+		body := newApimAlertBody(d)
+		httpr, err := pco.restclient.Put(authctx, getApimAlertPath(orgid, envid, apimid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update alert " + id + " of api " + apimid,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimAlertRead(ctx, d, m)
+}
+
+func resourceApimAlertDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimAlertId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimAlertPath(orgid, envid, apimid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete alert " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newApimAlertBody(d *schema.ResourceData) *apimAlert {
+	body := &apimAlert{
+		Name:     d.Get("name").(string),
+		Type:     d.Get("type").(string),
+		Severity: d.Get("severity").(string),
+		Enabled:  d.Get("enabled").(bool),
+		Condition: apimAlertCondition{
+			Operator:   d.Get("operator").(string),
+			Threshold:  d.Get("threshold").(int),
+			Period:     d.Get("period").(int),
+			PeriodUnit: d.Get("period_unit").(string),
+		},
+		Recipients: []apimAlertRecipient{},
+	}
+	switch body.Type {
+	case APIM_ALERT_TYPE_POLICY_VIOLATION:
+		body.Condition.PolicyId = d.Get("policy_id").(string)
+	case APIM_ALERT_TYPE_RESPONSE_TIME:
+		body.Condition.ResponseTime = d.Get("response_time").(int)
+	case APIM_ALERT_TYPE_RESPONSE_CODE:
+		body.Condition.ResponseCodes = ListInterface2ListStrings(d.Get("response_codes").([]interface{}))
+	}
+	for _, email := range ListInterface2ListStrings(d.Get("email_recipients").([]interface{})) {
+		body.Recipients = append(body.Recipients, apimAlertRecipient{Type: "email", Value: email})
+	}
+	for _, user := range ListInterface2ListStrings(d.Get("user_recipients").([]interface{})) {
+		body.Recipients = append(body.Recipients, apimAlertRecipient{Type: "user", Value: user})
+	}
+	return body
+}
+
+func flattenApimAlert(alert *apimAlert) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["name"] = alert.Name
+	result["type"] = alert.Type
+	result["severity"] = alert.Severity
+	result["enabled"] = alert.Enabled
+	result["operator"] = alert.Condition.Operator
+	result["threshold"] = alert.Condition.Threshold
+	result["period"] = alert.Condition.Period
+	result["period_unit"] = alert.Condition.PeriodUnit
+	result["policy_id"] = alert.Condition.PolicyId
+	result["response_time"] = alert.Condition.ResponseTime
+	result["response_codes"] = alert.Condition.ResponseCodes
+	emails := make([]string, 0)
+	users := make([]string, 0)
+	for _, recipient := range alert.Recipients {
+		switch recipient.Type {
+		case "email":
+			emails = append(emails, recipient.Value)
+		case "user":
+			users = append(users, recipient.Value)
+		}
+	}
+	result["email_recipients"] = emails
+	result["user_recipients"] = users
+	return result
+}
+
+// checks the condition attributes are consistent with the type of alert
+func validateApimAlertCondition(d *schema.ResourceDiff) error {
+	alert_type := d.Get("type").(string)
+	specifics := map[string]string{
+		APIM_ALERT_TYPE_POLICY_VIOLATION: "policy_id",
+		APIM_ALERT_TYPE_RESPONSE_TIME:    "response_time",
+		APIM_ALERT_TYPE_RESPONSE_CODE:    "response_codes",
+	}
+	for t, attr := range specifics {
+		// values computed during the apply (ex: the id of a new policy) are checked by the platform
+		if !d.NewValueKnown(attr) {
+			continue
+		}
+		_, ok := d.GetOk(attr)
+		if t == alert_type && !ok {
+			return fmt.Errorf("%s is required for %s alerts", attr, alert_type)
+		}
+		if t != alert_type && ok {
+			return fmt.Errorf("%s is only supported by %s alerts", attr, t)
+		}
+	}
+	if !d.NewValueKnown("email_recipients") || !d.NewValueKnown("user_recipients") {
+		return nil
+	}
+	emails := d.Get("email_recipients").([]interface{})
+	users := d.Get("user_recipients").([]interface{})
+	if len(emails)+len(users) == 0 {
+		return fmt.Errorf("at least one of email_recipients or user_recipients is required")
+	}
+	return nil
+}
+
+func getApimAlertAttributes() []string {
+	attributes := [...]string{
+		"name", "type", "severity", "enabled", "operator", "threshold", "period", "period_unit",
+		"policy_id", "response_time", "response_codes", "email_recipients", "user_recipients",
+	}
+	return attributes[:]
+}
+
+func getApimAlertUpdatableAttributes() []string {
+	return FilterStrList(getApimAlertAttributes(), func(s string) bool {
+		return s != "type"
+	})
+}
+
+func getApimAlertsPath(orgid, envid, apimid string) string {
+	return fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/environments/%s/apis/%s/alerts",
+		url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(apimid),
+	)
+}
+
+func getApimAlertPath(orgid, envid, apimid, id string) string {
+	return getApimAlertsPath(orgid, envid, apimid) + "/" + url.PathEscape(id)
+}
+
+func decomposeApimAlertId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
@@ -0,0 +1,310 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type apimApiGroup struct {
+	Id       int                   `json:"id,omitempty"`
+	Name     string                `json:"name"`
+	GroupId  string                `json:"groupId,omitempty"`
+	AssetId  string                `json:"assetId,omitempty"`
+	Versions []apimApiGroupVersion `json:"versions"`
+}
+
+type apimApiGroupVersion struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+func resourceApimApiGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimApiGroupCreate,
+		ReadContext:   resourceApimApiGroupRead,
+		UpdateContext: resourceApimApiGroupUpdate,
+		DeleteContext: resourceApimApiGroupDelete,
+		Description: `
+		Creates an API group in API Manager, published in Exchange as a bundle of APIs.
+		The api instances of each version of the group are linked per environment using ` + "`anypoint_apim_api_group_instance`" + `.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{group_id}",
+			},
+			"group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The api group id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api group is defined.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the api group.",
+			},
+			"asset_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The asset id of the api group in exchange. Derived from the name by default.",
+			},
+			"asset_group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The group id of the api group's asset in exchange.",
+			},
+			"versions": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The versions of the api group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the version, ex: v1",
+						},
+						"version_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The id of the version.",
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "group_id"}),
+		},
+	}
+}
+
+func resourceApimApiGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newApimApiGroupBody(d)
+	var res apimApiGroup
+	httpr, err := pco.restclient.Post(authctx, getApimApiGroupsPath(orgid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create api group " + body.Name,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, strconv.Itoa(res.Id)}))
+	return resourceApimApiGroupRead(ctx, d, m)
+}
+
+func resourceApimApiGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeApimApiGroupId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res apimApiGroup
+	httpr, err := pco.restclient.Get(authctx, getApimApiGroupPath(orgid, id), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read api group " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenApimApiGroup(&res)
+	for _, attr := range getApimApiGroupAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set api group " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, id}))
+	d.Set("org_id", orgid)
+	d.Set("group_id", id)
+	return diags
+}
+
+func resourceApimApiGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeApimApiGroupId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	if d.HasChange("name") {
+		body := map[string]interface{}{"name": d.Get("name")}
+		httpr, err := pco.restclient.Patch(authctx, getApimApiGroupPath(orgid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update api group " + id,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+	}
+	if d.HasChange("versions") {
+		old, new := d.GetChange("versions")
+		tocreate, todelete := getApimApiGroupVersionsChanges(old.([]interface{}), new.([]interface{}))
+		for _, name := range tocreate {
+			body := &apimApiGroupVersion{Name: name}
+			httpr, err := pco.restclient.Post(authctx, getApimApiGroupPath(orgid, id)+"/versions", body, nil)
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Unable to create version " + name + " of api group " + id,
+					Detail:   readRestClientErrorDetails(httpr, err),
+				})
+				return diags
+			}
+			httpr.Body.Close()
+		}
+		for _, versionid := range todelete {
+			httpr, err := pco.restclient.Delete(authctx, getApimApiGroupVersionPath(orgid, id, versionid))
+			if err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Unable to delete version " + versionid + " of api group " + id,
+					Detail:   readRestClientErrorDetails(httpr, err),
+				})
+				return diags
+			}
+			httpr.Body.Close()
+		}
+	}
+	d.Set("last_updated", time.Now().Format(time.RFC850))
+	return resourceApimApiGroupRead(ctx, d, m)
+}
+
+func resourceApimApiGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeApimApiGroupId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimApiGroupPath(orgid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete api group " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newApimApiGroupBody(d *schema.ResourceData) *apimApiGroup {
+	versions := d.Get("versions").([]interface{})
+	body := &apimApiGroup{
+		Name:     d.Get("name").(string),
+		Versions: make([]apimApiGroupVersion, len(versions)),
+	}
+	if val, ok := d.GetOk("asset_id"); ok {
+		body.AssetId = val.(string)
+	}
+	for i, v := range versions {
+		version := v.(map[string]interface{})
+		body.Versions[i] = apimApiGroupVersion{Name: version["name"].(string)}
+	}
+	return body
+}
+
+// returns the names of the versions to create and the ids of the versions to delete, versions are matched by name
+func getApimApiGroupVersionsChanges(old, new []interface{}) ([]string, []string) {
+	existing := make(map[string]string, len(old))
+	for _, v := range old {
+		version := v.(map[string]interface{})
+		existing[version["name"].(string)] = version["version_id"].(string)
+	}
+	tocreate := make([]string, 0)
+	for _, v := range new {
+		name := v.(map[string]interface{})["name"].(string)
+		if _, ok := existing[name]; ok {
+			delete(existing, name)
+		} else {
+			tocreate = append(tocreate, name)
+		}
+	}
+	todelete := make([]string, 0, len(existing))
+	for _, v := range old {
+		version := v.(map[string]interface{})
+		if id, ok := existing[version["name"].(string)]; ok {
+			todelete = append(todelete, id)
+		}
+	}
+	return tocreate, todelete
+}
+
+func flattenApimApiGroup(group *apimApiGroup) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["name"] = group.Name
+	result["asset_id"] = group.AssetId
+	result["asset_group_id"] = group.GroupId
+	versions := make([]interface{}, len(group.Versions))
+	for i, version := range group.Versions {
+		versions[i] = map[string]interface{}{
+			"name":       version.Name,
+			"version_id": strconv.Itoa(version.Id),
+		}
+	}
+	result["versions"] = versions
+	return result
+}
+
+func getApimApiGroupAttributes() []string {
+	return []string{"name", "asset_id", "asset_group_id", "versions"}
+}
+
+func getApimApiGroupsPath(orgid string) string {
+	return fmt.Sprintf("/apimanager/api/v1/organizations/%s/groups", url.PathEscape(orgid))
+}
+
+func getApimApiGroupPath(orgid, id string) string {
+	return getApimApiGroupsPath(orgid) + "/" + url.PathEscape(id)
+}
+
+func getApimApiGroupVersionPath(orgid, id, versionid string) string {
+	return getApimApiGroupPath(orgid, id) + "/versions/" + url.PathEscape(versionid)
+}
+
+func decomposeApimApiGroupId(d *schema.ResourceData) (string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1]
+}
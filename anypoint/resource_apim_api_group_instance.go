@@ -0,0 +1,391 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type apimApiGroupInstance struct {
+	Id                 int                       `json:"id,omitempty"`
+	EnvironmentId      string                    `json:"environmentId"`
+	GroupInstanceLabel string                    `json:"groupInstanceLabel"`
+	ApiInstances       []apimApiGroupInstanceApi `json:"apiInstances"`
+}
+
+type apimApiGroupInstanceApi struct {
+	Id int `json:"id"`
+}
+
+type apimSlaTierCollection struct {
+	Total int           `json:"total"`
+	Tiers []apimSlaTier `json:"tiers"`
+}
+
+func resourceApimApiGroupInstance() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimApiGroupInstanceCreate,
+		ReadContext:   resourceApimApiGroupInstanceRead,
+		UpdateContext: resourceApimApiGroupInstanceUpdate,
+		DeleteContext: resourceApimApiGroupInstanceDelete,
+		Description: `
+		Creates the instance of an API group version in an environment, linking the api instances of the environment to the group.
+		Client applications request access to the group instance on one of its SLA tiers.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{group_id}/{version_id}/{instance_id}",
+			},
+			"instance_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The api group instance id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api group is defined.",
+			},
+			"group_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api group id.",
+			},
+			"version_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the api group version.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where the api instances are defined.",
+			},
+			"label": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The label of the api group instance.",
+			},
+			"apim_ids": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The ids of the api manager instances of the environment that are part of the group, ex: the id of an `anypoint_apim_mule4` or `anypoint_apim_flexgateway`.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"tiers": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The SLA tiers of the api group instance. The tiers are matched by name.",
+				Elem: &schema.Resource{
+					Schema: getApimApiGroupInstanceTierSchema(),
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "group_id", "version_id", "instance_id"}),
+		},
+	}
+}
+
+// the tier attributes are the ones of the sla tier resource
+func getApimApiGroupInstanceTierSchema() map[string]*schema.Schema {
+	tier := resourceApimSlaTier().Schema
+	result := map[string]*schema.Schema{
+		"tier_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The SLA tier id.",
+		},
+	}
+	for _, attr := range []string{"name", "description", "auto_approve", "status", "limits"} {
+		result[attr] = tier[attr]
+	}
+	return result
+}
+
+func resourceApimApiGroupInstanceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	groupid := d.Get("group_id").(string)
+	versionid := d.Get("version_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//prepare body
+	body, err := newApimApiGroupInstanceBody(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to parse instance of api group " + groupid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	//perform request
+	var res apimApiGroupInstance
+	path := getApimApiGroupVersionPath(orgid, groupid, versionid) + "/instances"
+	httpr, err := pco.restclient.Post(authctx, path, body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create instance of api group " + groupid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	id := strconv.Itoa(res.Id)
+	d.SetId(ComposeResourceId([]string{orgid, groupid, versionid, id}))
+	if diags := applyApimApiGroupInstanceTiers(ctx, &pco, d, []interface{}{}, d.Get("tiers").([]interface{})); diags.HasError() {
+		return diags
+	}
+	return resourceApimApiGroupInstanceRead(ctx, d, m)
+}
+
+func resourceApimApiGroupInstanceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, groupid, versionid, id := decomposeApimApiGroupInstanceId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res apimApiGroupInstance
+	path := getApimApiGroupInstancePath(orgid, groupid, versionid, id)
+	httpr, err := pco.restclient.Get(authctx, path, nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read instance " + id + " of api group " + groupid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	var tiers apimSlaTierCollection
+	httpr, err = pco.restclient.Get(authctx, path+"/tiers", nil, &tiers)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read tiers of instance " + id + " of api group " + groupid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	sortApimApiGroupInstanceTiers(tiers.Tiers, d.Get("tiers").([]interface{}))
+	data := flattenApimApiGroupInstance(&res, tiers.Tiers)
+	for _, attr := range getApimApiGroupInstanceAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set instance " + id + " of api group " + groupid + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, groupid, versionid, id}))
+	d.Set("org_id", orgid)
+	d.Set("group_id", groupid)
+	d.Set("version_id", versionid)
+	d.Set("instance_id", id)
+	return diags
+}
+
+func resourceApimApiGroupInstanceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, groupid, versionid, id := decomposeApimApiGroupInstanceId(d)
+	if d.HasChanges("label", "apim_ids") {
+		body, err := newApimApiGroupInstanceBody(d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to parse instance " + id + " of api group " + groupid,
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		authctx := getRestAuthCtx(ctx, &pco)
+		httpr, err := pco.restclient.Patch(authctx, getApimApiGroupInstancePath(orgid, groupid, versionid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update instance " + id + " of api group " + groupid,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+	}
+	if d.HasChange("tiers") {
+		old, new := d.GetChange("tiers")
+		if diags := applyApimApiGroupInstanceTiers(ctx, &pco, d, old.([]interface{}), new.([]interface{})); diags.HasError() {
+			return diags
+		}
+	}
+	d.Set("last_updated", time.Now().Format(time.RFC850))
+	return resourceApimApiGroupInstanceRead(ctx, d, m)
+}
+
+func resourceApimApiGroupInstanceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, groupid, versionid, id := decomposeApimApiGroupInstanceId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimApiGroupInstancePath(orgid, groupid, versionid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete instance " + id + " of api group " + groupid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+// creates, updates and deletes the tiers of the group instance, the tiers are matched by name
+func applyApimApiGroupInstanceTiers(ctx context.Context, pco *ProviderConfOutput, d *schema.ResourceData, old, new []interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	orgid, groupid, versionid, id := decomposeApimApiGroupInstanceId(d)
+	path := getApimApiGroupInstancePath(orgid, groupid, versionid, id) + "/tiers"
+	authctx := getRestAuthCtx(ctx, pco)
+	existing := make(map[string]map[string]interface{}, len(old))
+	for _, t := range old {
+		tier := t.(map[string]interface{})
+		existing[tier["name"].(string)] = tier
+	}
+	for _, t := range new {
+		tier := t.(map[string]interface{})
+		name := tier["name"].(string)
+		body := newApimSlaTierBodyFromMap(tier)
+		prev, ok := existing[name]
+		delete(existing, name)
+		var httpr *http.Response
+		var err error
+		if !ok {
+			httpr, err = pco.restclient.Post(authctx, path, body, nil)
+		} else if !reflect.DeepEqual(newApimSlaTierBodyFromMap(prev), body) {
+			httpr, err = pco.restclient.Put(authctx, path+"/"+url.PathEscape(prev["tier_id"].(string)), body, nil)
+		} else {
+			continue
+		}
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to apply tier " + name + " of api group instance " + id,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		httpr.Body.Close()
+	}
+	for name, tier := range existing {
+		httpr, err := pco.restclient.Delete(authctx, path+"/"+url.PathEscape(tier["tier_id"].(string)))
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to delete tier " + name + " of api group instance " + id,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		httpr.Body.Close()
+	}
+	return diags
+}
+
+// sorts the tiers in the order of the configuration, the unknown tiers are kept last
+func sortApimApiGroupInstanceTiers(tiers []apimSlaTier, configured []interface{}) {
+	positions := make(map[string]int, len(configured))
+	for i, t := range configured {
+		positions[t.(map[string]interface{})["name"].(string)] = i
+	}
+	position := func(name string) int {
+		if i, ok := positions[name]; ok {
+			return i
+		}
+		return len(configured)
+	}
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return position(tiers[i].Name) < position(tiers[j].Name)
+	})
+}
+
+func newApimApiGroupInstanceBody(d *schema.ResourceData) (*apimApiGroupInstance, error) {
+	apimids := d.Get("apim_ids").(*schema.Set).List()
+	body := &apimApiGroupInstance{
+		EnvironmentId:      d.Get("env_id").(string),
+		GroupInstanceLabel: d.Get("label").(string),
+		ApiInstances:       make([]apimApiGroupInstanceApi, len(apimids)),
+	}
+	for i, val := range apimids {
+		apimid, err := strconv.Atoi(val.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid api instance id %s: %w", val, err)
+		}
+		body.ApiInstances[i] = apimApiGroupInstanceApi{Id: apimid}
+	}
+	return body, nil
+}
+
+func flattenApimApiGroupInstance(instance *apimApiGroupInstance, tiers []apimSlaTier) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["env_id"] = instance.EnvironmentId
+	result["label"] = instance.GroupInstanceLabel
+	apimids := make([]interface{}, len(instance.ApiInstances))
+	for i, api := range instance.ApiInstances {
+		apimids[i] = strconv.Itoa(api.Id)
+	}
+	result["apim_ids"] = apimids
+	list := make([]interface{}, len(tiers))
+	for i, tier := range tiers {
+		item := flattenApimSlaTier(&tier)
+		delete(item, "application_count")
+		item["tier_id"] = strconv.Itoa(tier.Id)
+		list[i] = item
+	}
+	result["tiers"] = list
+	return result
+}
+
+func getApimApiGroupInstanceAttributes() []string {
+	return []string{"env_id", "label", "apim_ids", "tiers"}
+}
+
+func getApimApiGroupInstancePath(orgid, groupid, versionid, id string) string {
+	return getApimApiGroupVersionPath(orgid, groupid, versionid) + "/instances/" + url.PathEscape(id)
+}
+
+func decomposeApimApiGroupInstanceId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
@@ -0,0 +1,683 @@
+package anypoint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/iancoleman/strcase"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim_policy"
+)
+
+const APIM_POLICY_MULESOFT_GROUP_ID = "68ef9520-24e9-4cf2-b2f5-620025690913"
+
+// a typed configuration block borrowed from an api instance policy resource
+type apimAutomatedPolicyTypedCfg struct {
+	Resource func() *schema.Resource
+	Expand   func(map[string]interface{}) map[string]interface{}
+	// optional, the keys are converted to snake case by default
+	Flatten func(map[string]interface{}) map[string]interface{}
+}
+
+var APIM_AUTOMATED_POLICY_TYPED_CFGS = map[string]apimAutomatedPolicyTypedCfg{
+	"client_id_enforcement": {
+		Resource: resourceApimInstancePolicyClientIdEnf,
+		Expand:   newApimPolicyClientIdEnfCfg,
+	},
+	"message_logging": {
+		Resource: resourceApimInstancePolicyMessageLogging,
+		Expand:   newApimPolicyMessageLoggingCfg,
+		Flatten: func(cfg map[string]interface{}) map[string]interface{} {
+			policy := apim_policy.NewApimPolicy()
+			policy.SetConfigurationData(cfg)
+			return flattenApimPolicyMessageLoggingCfg(nil, policy)
+		},
+	},
+	"rate_limiting": {
+		Resource: resourceApimInstancePolicyRateLimiting,
+		Expand:   newApimPolicyRateLimitingCfg,
+	},
+	"rate_limiting_sla_based": {
+		Resource: resourceApimInstancePolicyRateLimitingSla,
+		Expand:   newApimPolicyRateLimitingSlaCfg,
+	},
+	"spike_control": {
+		Resource: resourceApimInstancePolicySpikeControl,
+		Expand:   newApimPolicySpikeControlCfg,
+	},
+	"ip_allowlist": {
+		Resource: resourceApimInstancePolicyIpAllowlist,
+		Expand:   newApimPolicyIpAllowlistCfg,
+	},
+	"ip_blocklist": {
+		Resource: resourceApimInstancePolicyIpBlocklist,
+		Expand:   newApimPolicyIpBlocklistCfg,
+	},
+	"cors": {
+		Resource: resourceApimInstancePolicyCors,
+		Expand:   newApimPolicyCorsCfg,
+		Flatten: func(cfg map[string]interface{}) map[string]interface{} {
+			policy := apim_policy.NewApimPolicy()
+			policy.SetConfigurationData(cfg)
+			return flattenApimPolicyCorsCfg(nil, policy)
+		},
+	},
+	"header_injection": {
+		Resource: resourceApimInstancePolicyHeaderInjection,
+		Expand:   newApimPolicyHeaderInjectionCfg,
+	},
+	"header_removal": {
+		Resource: resourceApimInstancePolicyHeaderRemoval,
+		Expand:   newApimPolicyHeaderRemovalCfg,
+	},
+	"http_caching": {
+		Resource: resourceApimInstancePolicyHttpCaching,
+		Expand:   newApimPolicyHttpCachingCfg,
+	},
+	"json_threat_protection": {
+		Resource: resourceApimInstancePolicyJsonThreatProtection,
+		Expand:   newApimPolicyJsonThreatProtectionCfg,
+	},
+	"xml_threat_protection": {
+		Resource: resourceApimInstancePolicyXmlThreatProtection,
+		Expand:   newApimPolicyXmlThreatProtectionCfg,
+	},
+}
+
+// the rule of application of the automated policies, the technologies aren't part of the client model
+type apimAutomatedPolicyRule struct {
+	EnvironmentId  string                          `json:"environmentId"`
+	OrganizationId string                          `json:"organizationId"`
+	Range          *apimAutomatedPolicyRange       `json:"range,omitempty"`
+	Technologies   []apimAutomatedPolicyTechnology `json:"technologies,omitempty"`
+}
+
+type apimAutomatedPolicyTechnology struct {
+	Technology string                    `json:"technology"`
+	Range      *apimAutomatedPolicyRange `json:"range,omitempty"`
+}
+
+type apimAutomatedPolicyRange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+func resourceApimAutomatedPolicy() *schema.Resource {
+	cfgs := getApimAutomatedPolicyCfgAttributes()
+	s := map[string]*schema.Schema{
+		"last_updated": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Description: "The last time this resource has been updated locally.",
+		},
+		"id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The automated policy's unique id",
+		},
+		"org_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The organization id where the automated policy is defined.",
+		},
+		"env_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "The environment id where the automated policy is applied.",
+		},
+		"audit": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "The automated policy's auditing data",
+		},
+		"configuration_data": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ExactlyOneOf:     cfgs,
+			Description:      "The policy configuration data in json format, for policy templates without typed configuration block.",
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsJSON),
+			DiffSuppressFunc: diffSuppressApimPolicyCustomCfg,
+		},
+		"rule_of_application": {
+			Type:        schema.TypeList,
+			Required:    true,
+			MinItems:    1,
+			Description: "The runtimes the policy is applied to, by technology and version range.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"technology": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "The api instances technology, either mule4 or flexGateway.",
+						ValidateDiagFunc: validation.ToDiagFunc(
+							validation.StringInSlice([]string{APIM_MULE4_TECHNOLOGY, FLEX_GATEWAY_TECHNOLOGY}, false),
+						),
+					},
+					"range_from": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The minimum runtime version (inclusive) the policy is applied to.",
+					},
+					"range_to": {
+						Type:        schema.TypeString,
+						Optional:    true,
+						Description: "The maximum runtime version (inclusive) the policy is applied to.",
+					},
+				},
+			},
+		},
+		"order": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The policy order.",
+		},
+		"disabled": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Whether the policy is disabled.",
+		},
+		"pointcut_data": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The method & resource conditions",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"method_regex": {
+						Type:        schema.TypeSet,
+						Required:    true,
+						Description: "The list of HTTP methods",
+						Elem: &schema.Schema{
+							Type: schema.TypeString,
+							ValidateDiagFunc: validation.ToDiagFunc(
+								validation.StringInSlice(
+									[]string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "TRACE"},
+									false,
+								),
+							),
+						},
+					},
+					"uri_template_regex": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "URI template regex",
+					},
+				},
+			},
+		},
+		"asset_group_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			ForceNew:    true,
+			Default:     APIM_POLICY_MULESOFT_GROUP_ID,
+			Description: "The policy template group id in anypoint exchange.",
+		},
+		"asset_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			ForceNew:    true,
+			Description: "The policy template id in anypoint exchange. Defaults to the template of the typed configuration block, required when using configuration_data.",
+		},
+		"asset_version": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    true,
+			Description: "the policy template version in anypoint exchange.",
+		},
+	}
+	for name, t := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		s[name] = &schema.Schema{
+			Type:         schema.TypeList,
+			Optional:     true,
+			MaxItems:     1,
+			ExactlyOneOf: cfgs,
+			Description:  fmt.Sprintf("The policy configuration data of a %s policy.", name),
+			Elem:         t.Resource().Schema["configuration_data"].Elem,
+		}
+	}
+	return &schema.Resource{
+		CreateContext: resourceApimAutomatedPolicyCreate,
+		ReadContext:   resourceApimAutomatedPolicyRead,
+		UpdateContext: resourceApimAutomatedPolicyUpdate,
+		DeleteContext: resourceApimAutomatedPolicyDelete,
+		Description: `
+		Create and manage an automated policy, applied to every API instance of an environment matching the rule of application.
+		The configuration is either given by one of the typed configuration blocks (the same as the corresponding API policy resource)
+		or in json format through ` + "`configuration_data`" + ` for any other policy template.
+		`,
+		Schema: s,
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			return validateApimAutomatedPolicyCfg(rd)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceApimAutomatedPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//prepare body
+	body, err := newApimAutomatedPolicyBody(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to parse automated policy configuration",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	//perform request
+	// the client doesn't provide the write operations of the automated policies
+	var res apim_policy.AutomatedPolicy
+	path := fmt.Sprintf("/apimanager/api/v1/organizations/%s/automated-policies", url.PathEscape(orgid))
+	httpr, err := pco.restclient.Post(authctx, path, body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create automated policy in org " + orgid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(strconv.Itoa(int(res.GetId())))
+	return resourceApimAutomatedPolicyRead(ctx, d, m)
+}
+
+func resourceApimAutomatedPolicyRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	id := d.Id()
+	if isComposedResourceId(id) {
+		orgid, id = decomposeApimAutomatedPolicyId(d)
+	}
+	res, rule, diags := getApimAutomatedPolicyById(ctx, &pco, orgid, id)
+	if diags.HasError() {
+		return diags
+	}
+	if res == nil {
+		log.Printf("[WARN] automated policy %s not found in org %s, removing it from the state", id, orgid)
+		d.SetId("")
+		return diags
+	}
+	//process data
+	d.SetId(id)
+	d.Set("org_id", orgid)
+	if err := setApimAutomatedPolicyCfgToResourceData(ctx, &pco, d, res); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set configuration of automated policy " + id,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	data := flattenApimAutomatedPolicy(res, rule)
+	for _, attr := range getApimAutomatedPolicyAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set automated policy " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	return diags
+}
+
+func resourceApimAutomatedPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	attributes := append(getApimAutomatedPolicyCfgAttributes(), "rule_of_application", "pointcut_data", "disabled")
+	if d.HasChanges(attributes...) {
+		pco := m.(ProviderConfOutput)
+		orgid := d.Get("org_id").(string)
+		id := d.Id()
+		authctx := getRestAuthCtx(ctx, &pco)
+		//prepare body
+		body, err := newApimAutomatedPolicyBody(d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to parse automated policy configuration",
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		//perform request
+		httpr, err := pco.restclient.Patch(authctx, getApimAutomatedPolicyPath(orgid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update automated policy " + id,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimAutomatedPolicyRead(ctx, d, m)
+}
+
+func resourceApimAutomatedPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	id := d.Id()
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimAutomatedPolicyPath(orgid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete automated policy " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newApimAutomatedPolicyBody(d *schema.ResourceData) (map[string]interface{}, error) {
+	body := make(map[string]interface{})
+	cfg, err := newApimAutomatedPolicyCfg(d)
+	if err != nil {
+		return nil, err
+	}
+	body["configurationData"] = cfg
+	if val, ok := d.GetOk("pointcut_data"); ok {
+		body["pointcutData"] = newApimPolicyCustomPointcutDataBody(val.([]interface{}))
+	} else {
+		body["pointcutData"] = nil
+	}
+	rule := apimAutomatedPolicyRule{
+		EnvironmentId:  d.Get("env_id").(string),
+		OrganizationId: d.Get("org_id").(string),
+	}
+	for _, item := range d.Get("rule_of_application").([]interface{}) {
+		data := item.(map[string]interface{})
+		technology := apimAutomatedPolicyTechnology{Technology: data["technology"].(string)}
+		from := data["range_from"].(string)
+		to := data["range_to"].(string)
+		if from != "" || to != "" {
+			technology.Range = &apimAutomatedPolicyRange{From: from, To: to}
+		}
+		rule.Technologies = append(rule.Technologies, technology)
+	}
+	body["ruleOfApplication"] = rule
+	body["groupId"] = d.Get("asset_group_id").(string)
+	body["assetId"] = d.Get("asset_id").(string)
+	body["assetVersion"] = d.Get("asset_version").(string)
+	body["disabled"] = d.Get("disabled").(bool)
+	return body, nil
+}
+
+// returns the configuration data from the typed configuration block or the json configuration
+func newApimAutomatedPolicyCfg(d *schema.ResourceData) (map[string]interface{}, error) {
+	for name, t := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		if l, ok := d.Get(name).([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			return t.Expand(l[0].(map[string]interface{})), nil
+		}
+	}
+	var cfg map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("configuration_data").(string)), &cfg); err != nil {
+		return nil, fmt.Errorf("configuration_data expected to be a valid JSON Object. %s", err.Error())
+	}
+	return cfg, nil
+}
+
+/*
+Sets the server configuration data into the typed configuration block in use or the json configuration.
+On import, none of them is set: the typed block matching the policy template is used if any.
+*/
+func setApimAutomatedPolicyCfgToResourceData(ctx context.Context, pco *ProviderConfOutput, d *schema.ResourceData, res *apim_policy.AutomatedPolicy) error {
+	name, t, ok := findApimAutomatedPolicyTypedCfgInUse(d)
+	if !ok && d.Get("configuration_data").(string) == "" {
+		name, t, ok = findApimAutomatedPolicyTypedCfgByAsset(res.GetGroupId(), res.GetAssetId())
+	}
+	if ok {
+		var data map[string]interface{}
+		if t.Flatten != nil {
+			data = t.Flatten(res.GetConfigurationData())
+		} else {
+			data = flattenApimAutomatedPolicyTypedCfg(t.Resource().Schema["configuration_data"].Elem.(*schema.Resource), res.GetConfigurationData())
+		}
+		dst := make(map[string]interface{})
+		if l, ok := d.Get(name).([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			dst = l[0].(map[string]interface{})
+		}
+		maps.Copy(dst, data)
+		return d.Set(name, []interface{}{dst})
+	}
+	policy := apim_policy.NewApimPolicy()
+	policy.SetConfigurationData(res.GetConfigurationData())
+	defaults := func() map[string]interface{} {
+		return getApimPolicyCustomTemplateDefaults(ctx, pco, d.Get("org_id").(string), res.GetGroupId(), res.GetAssetId(), res.GetAssetVersion())
+	}
+	cfg, err := flattenApimPolicyCustomCfg(d, policy, defaults)
+	if err != nil {
+		return err
+	}
+	return d.Set("configuration_data", cfg)
+}
+
+// returns the typed configuration block set in the resource data
+func findApimAutomatedPolicyTypedCfgInUse(d *schema.ResourceData) (string, apimAutomatedPolicyTypedCfg, bool) {
+	for name, t := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		if l, ok := d.Get(name).([]interface{}); ok && len(l) > 0 && l[0] != nil {
+			return name, t, true
+		}
+	}
+	return "", apimAutomatedPolicyTypedCfg{}, false
+}
+
+// returns the typed configuration block of the given policy template
+func findApimAutomatedPolicyTypedCfgByAsset(groupid, assetid string) (string, apimAutomatedPolicyTypedCfg, bool) {
+	if groupid != APIM_POLICY_MULESOFT_GROUP_ID {
+		return "", apimAutomatedPolicyTypedCfg{}, false
+	}
+	for name, t := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		if t.Resource().Schema["asset_id"].Default == assetid {
+			return name, t, true
+		}
+	}
+	return "", apimAutomatedPolicyTypedCfg{}, false
+}
+
+/*
+Returns the automated policy with the given id, nil if it doesn't exist.
+The rule of application technologies aren't part of the client model, they are decoded from the raw body of the response.
+*/
+func getApimAutomatedPolicyById(ctx context.Context, pco *ProviderConfOutput, orgid, id string) (*apim_policy.AutomatedPolicy, *apimAutomatedPolicyRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	authctx := getApimPolicyAuthCtx(ctx, pco)
+	res, httpr, err := pco.apimpolicyclient.DefaultApi.GetOrgAutomatedPolicies(authctx, orgid).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read automated policy " + id,
+			Detail:   details,
+		})
+		return nil, nil, diags
+	}
+	defer httpr.Body.Close()
+	var raw struct {
+		AutomatedPolicies []struct {
+			Id                int32                   `json:"id"`
+			RuleOfApplication apimAutomatedPolicyRule `json:"ruleOfApplication"`
+		} `json:"automatedPolicies"`
+	}
+	if err := json.NewDecoder(httpr.Body).Decode(&raw); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to parse the rule of application of automated policy " + id,
+			Detail:   err.Error(),
+		})
+		return nil, nil, diags
+	}
+	for _, policy := range res.GetAutomatedPolicies() {
+		if strconv.Itoa(int(policy.GetId())) != id {
+			continue
+		}
+		roa := policy.GetRuleOfApplication()
+		rule := &apimAutomatedPolicyRule{
+			EnvironmentId:  roa.GetEnvironmentId(),
+			OrganizationId: roa.GetOrganizationId(),
+		}
+		for _, item := range raw.AutomatedPolicies {
+			if item.Id == policy.GetId() {
+				rule = &item.RuleOfApplication
+				break
+			}
+		}
+		return &policy, rule, diags
+	}
+	return nil, nil, diags
+}
+
+// converts the configuration keys to snake case, only the attributes of the block schema are kept
+func flattenApimAutomatedPolicyTypedCfg(elem *schema.Resource, cfg map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range cfg {
+		attr := strcase.ToSnake(k)
+		s, ok := elem.Schema[attr]
+		if !ok {
+			continue
+		}
+		nested, ok := s.Elem.(*schema.Resource)
+		items, is_list := v.([]interface{})
+		if !ok || !is_list {
+			result[attr] = v
+			continue
+		}
+		l := make([]interface{}, len(items))
+		for i, item := range items {
+			if obj, ok := item.(map[string]interface{}); ok {
+				l[i] = flattenApimAutomatedPolicyTypedCfg(nested, obj)
+			} else {
+				l[i] = item
+			}
+		}
+		result[attr] = l
+	}
+	return result
+}
+
+func flattenApimAutomatedPolicy(policy *apim_policy.AutomatedPolicy, rule *apimAutomatedPolicyRule) map[string]interface{} {
+	result := make(map[string]interface{})
+	if val, ok := policy.GetAuditOk(); ok {
+		result["audit"] = flattenApimInstancePolicyAudit(val)
+	}
+	result["env_id"] = rule.EnvironmentId
+	result["order"] = int(policy.GetOrder())
+	result["disabled"] = policy.GetDisabled()
+	result["pointcut_data"] = flattenApimInstancePolicyPointcutData(policy.GetPointcutData())
+	result["asset_group_id"] = policy.GetGroupId()
+	result["asset_id"] = policy.GetAssetId()
+	result["asset_version"] = policy.GetAssetVersion()
+	result["rule_of_application"] = flattenApimAutomatedPolicyRule(rule)
+	return result
+}
+
+func flattenApimAutomatedPolicyRule(rule *apimAutomatedPolicyRule) []interface{} {
+	// older automated policies only define a mule 4 version range
+	if len(rule.Technologies) == 0 {
+		item := map[string]interface{}{"technology": APIM_MULE4_TECHNOLOGY}
+		if rule.Range != nil {
+			item["range_from"] = rule.Range.From
+			item["range_to"] = rule.Range.To
+		}
+		return []interface{}{item}
+	}
+	slice := make([]interface{}, len(rule.Technologies))
+	for i, technology := range rule.Technologies {
+		item := map[string]interface{}{"technology": technology.Technology}
+		if technology.Range != nil {
+			item["range_from"] = technology.Range.From
+			item["range_to"] = technology.Range.To
+		}
+		slice[i] = item
+	}
+	return slice
+}
+
+func validateApimAutomatedPolicyCfg(d *schema.ResourceDiff) error {
+	for name, t := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		if l, ok := d.Get(name).([]interface{}); !ok || len(l) == 0 {
+			continue
+		}
+		assetid := t.Resource().Schema["asset_id"].Default.(string)
+		if !d.NewValueKnown("asset_id") {
+			return nil
+		}
+		if val := d.Get("asset_id").(string); val == "" {
+			return d.SetNew("asset_id", assetid)
+		} else if val != assetid {
+			return fmt.Errorf("asset_id %q doesn't match the %s configuration block, expected %q", val, name, assetid)
+		}
+		return nil
+	}
+	if d.NewValueKnown("asset_id") && d.Get("asset_id").(string) == "" {
+		return fmt.Errorf("attribute asset_id is required when using configuration_data")
+	}
+	return nil
+}
+
+// returns the attributes holding the policy configuration, sorted for stable schemas
+func getApimAutomatedPolicyCfgAttributes() []string {
+	attributes := []string{"configuration_data"}
+	for name := range APIM_AUTOMATED_POLICY_TYPED_CFGS {
+		attributes = append(attributes, name)
+	}
+	sort.Strings(attributes)
+	return attributes
+}
+
+func getApimAutomatedPolicyAttributes() []string {
+	return []string{
+		"audit", "env_id", "order", "disabled", "pointcut_data", "asset_group_id",
+		"asset_id", "asset_version", "rule_of_application",
+	}
+}
+
+func getApimAutomatedPolicyPath(orgid, id string) string {
+	return fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/automated-policies/%s",
+		url.PathEscape(orgid), url.PathEscape(id),
+	)
+}
+
+func decomposeApimAutomatedPolicyId(d *schema.ResourceData) (string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1]
+}
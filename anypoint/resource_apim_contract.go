@@ -0,0 +1,366 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	APIM_CONTRACT_STATUS_PENDING  = "PENDING"
+	APIM_CONTRACT_STATUS_APPROVED = "APPROVED"
+	APIM_CONTRACT_STATUS_REJECTED = "REJECTED"
+	APIM_CONTRACT_STATUS_REVOKED  = "REVOKED"
+)
+
+type apimContract struct {
+	Id              int                      `json:"id"`
+	Status          string                   `json:"status"`
+	ApplicationId   int                      `json:"applicationId"`
+	TierId          *int                     `json:"tierId,omitempty"`
+	RequestedTierId *int                     `json:"requestedTierId,omitempty"`
+	ApprovedDate    string                   `json:"approvedDate,omitempty"`
+	RejectedDate    string                   `json:"rejectedDate,omitempty"`
+	RevokedDate     string                   `json:"revokedDate,omitempty"`
+	Application     *apimContractApplication `json:"application,omitempty"`
+}
+
+type apimContractApplication struct {
+	Name     string `json:"name"`
+	ClientId string `json:"clientId"`
+}
+
+type apimContractRequest struct {
+	ApplicationId   int  `json:"applicationId"`
+	RequestedTierId *int `json:"requestedTierId,omitempty"`
+	AcceptedTerms   bool `json:"acceptedTerms"`
+}
+
+func resourceApimContract() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimContractCreate,
+		ReadContext:   resourceApimContractRead,
+		UpdateContext: resourceApimContractUpdate,
+		DeleteContext: resourceApimContractDelete,
+		Description: `
+		Creates a contract between a client application and an API Manager instance, optionally on an SLA tier.
+		Once approved, the client application's credentials are accepted by the ` + "`anypoint_apim_policy_client_id_enforcement`" + ` policy.
+		Contracts pending approval are approved by the provider when the desired status is ` + "`APPROVED`" + `.
+		NOTE: The contract is revoked before being deleted.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{apim_id}/{contract_id}",
+			},
+			"contract_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The contract id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api manager instance id the client application requests access to.",
+			},
+			"application_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the client application requesting access to the api instance.",
+			},
+			"tier_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The id of the requested SLA tier. Required when the api instance defines SLA tiers.",
+			},
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          APIM_CONTRACT_STATUS_APPROVED,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{APIM_CONTRACT_STATUS_APPROVED, APIM_CONTRACT_STATUS_REVOKED}, false)),
+				Description: `
+				The status of the contract. Set to ` + "`REVOKED`" + ` to revoke the access of the client application and back to ` + "`APPROVED`" + ` to restore it.
+				Supported values are ` + "`APPROVED` and `REVOKED`" + `.
+				`,
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The client id of the client application.",
+			},
+			"application_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the client application.",
+			},
+			"approved_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the contract was approved.",
+			},
+			"revoked_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The date the contract was revoked.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "env_id", "apim_id", "contract_id"}),
+		},
+	}
+}
+
+func resourceApimContractCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//prepare body
+	body, err := newApimContractBody(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to parse contract for api " + apimid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	//perform request
+	var res apimContract
+	httpr, err := pco.restclient.Post(authctx, getApimContractsPath(orgid, envid, apimid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create contract for api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	id := strconv.Itoa(res.Id)
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, id}))
+	if diags := applyApimContractStatus(ctx, &pco, orgid, envid, apimid, id, res.Status, d.Get("status").(string)); diags.HasError() {
+		return diags
+	}
+	return resourceApimContractRead(ctx, d, m)
+}
+
+func resourceApimContractRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimContractId(d)
+	res, diags := getApimContract(ctx, &pco, orgid, envid, apimid, id)
+	if diags.HasError() {
+		return diags
+	}
+	//process data
+	data := flattenApimContract(res)
+	for _, attr := range getApimContractAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set contract " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, id}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("apim_id", apimid)
+	d.Set("contract_id", id)
+	return diags
+}
+
+func resourceApimContractUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimContractId(d)
+	if d.HasChange("status") {
+		old, new := d.GetChange("status")
+		if diags := applyApimContractStatus(ctx, &pco, orgid, envid, apimid, id, old.(string), new.(string)); diags.HasError() {
+			return diags
+		}
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimContractRead(ctx, d, m)
+}
+
+func resourceApimContractDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimContractId(d)
+	// only revoked contracts can be deleted
+	res, diags := getApimContract(ctx, &pco, orgid, envid, apimid, id)
+	if diags.HasError() {
+		return diags
+	}
+	if diags := applyApimContractStatus(ctx, &pco, orgid, envid, apimid, id, res.Status, APIM_CONTRACT_STATUS_REVOKED); diags.HasError() {
+		return diags
+	}
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimContractPath(orgid, envid, apimid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete contract " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func getApimContract(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid, id string) (*apimContract, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	authctx := getRestAuthCtx(ctx, pco)
+	var res apimContract
+	httpr, err := pco.restclient.Get(authctx, getApimContractPath(orgid, envid, apimid, id), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read contract " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return nil, diags
+	}
+	defer httpr.Body.Close()
+	return &res, diags
+}
+
+// moves the contract from its current status to the desired one going through the required transitions
+func applyApimContractStatus(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid, id, current, desired string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	authctx := getRestAuthCtx(ctx, pco)
+	for _, action := range getApimContractStatusActions(current, desired) {
+		path := getApimContractPath(orgid, envid, apimid, id) + "/" + action
+		httpr, err := pco.restclient.Post(authctx, path, nil, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to " + action + " contract " + id + " of api " + apimid,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		httpr.Body.Close()
+	}
+	return diags
+}
+
+// returns the actions to perform on a contract to move it from its current status to the desired one
+func getApimContractStatusActions(current, desired string) []string {
+	switch desired {
+	case APIM_CONTRACT_STATUS_APPROVED:
+		switch current {
+		case APIM_CONTRACT_STATUS_PENDING, APIM_CONTRACT_STATUS_REJECTED:
+			return []string{"approve"}
+		case APIM_CONTRACT_STATUS_REVOKED:
+			return []string{"restore"}
+		}
+	case APIM_CONTRACT_STATUS_REVOKED:
+		switch current {
+		case APIM_CONTRACT_STATUS_PENDING, APIM_CONTRACT_STATUS_REJECTED:
+			// only approved contracts can be revoked
+			return []string{"approve", "revoke"}
+		case APIM_CONTRACT_STATUS_APPROVED:
+			return []string{"revoke"}
+		}
+	}
+	return []string{}
+}
+
+func newApimContractBody(d *schema.ResourceData) (*apimContractRequest, error) {
+	appid, err := strconv.Atoi(d.Get("application_id").(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid application_id: %w", err)
+	}
+	body := &apimContractRequest{
+		ApplicationId: appid,
+		AcceptedTerms: true,
+	}
+	if val, ok := d.GetOk("tier_id"); ok {
+		tierid, err := strconv.Atoi(val.(string))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tier_id: %w", err)
+		}
+		body.RequestedTierId = &tierid
+	}
+	return body, nil
+}
+
+func flattenApimContract(contract *apimContract) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["status"] = contract.Status
+	result["application_id"] = strconv.Itoa(contract.ApplicationId)
+	// the tier is only assigned once the contract is approved
+	if contract.TierId != nil {
+		result["tier_id"] = strconv.Itoa(*contract.TierId)
+	} else if contract.RequestedTierId != nil {
+		result["tier_id"] = strconv.Itoa(*contract.RequestedTierId)
+	}
+	result["approved_date"] = contract.ApprovedDate
+	result["revoked_date"] = contract.RevokedDate
+	if app := contract.Application; app != nil {
+		result["client_id"] = app.ClientId
+		result["application_name"] = app.Name
+	}
+	return result
+}
+
+func getApimContractAttributes() []string {
+	return []string{
+		"status", "application_id", "tier_id", "approved_date",
+		"revoked_date", "client_id", "application_name",
+	}
+}
+
+func getApimContractsPath(orgid, envid, apimid string) string {
+	return fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/environments/%s/apis/%s/contracts",
+		url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(apimid),
+	)
+}
+
+func getApimContractPath(orgid, envid, apimid, id string) string {
+	return getApimContractsPath(orgid, envid, apimid) + "/" + url.PathEscape(id)
+}
+
+func decomposeApimContractId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
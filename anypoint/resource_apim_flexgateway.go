@@ -466,6 +466,50 @@ func resourceApimFlexGateway() *schema.Resource {
 					},
 				},
 			},
+			"allow_unused_upstreams": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether upstreams not referenced by any route are accepted (i.e. staged upstreams), they are rejected during the plan otherwise.",
+			},
+			"upstreams_status": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Description: `
+				The status of each upstream as applied by api manager, read from the upstreams and routing of the instance.
+				Api manager doesn't expose the health of the upstreams, the status reflects how the traffic is routed to them.
+				`,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"upstream_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The upstream's id",
+						},
+						"label": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The upstream's label",
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+							Description: `
+							The upstream's status, one of ` + "`routed`" + ` (receives traffic from at least one route),
+							` + "`disabled`" + ` (only referenced with a weight of 0) or ` + "`unused`" + ` (not referenced by any route).
+							`,
+						},
+						"routes": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The labels of the routes referencing the upstream.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
 			"status": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -478,7 +522,13 @@ func resourceApimFlexGateway() *schema.Resource {
 			},
 		},
 		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
-			return validateRoutingUpstreams(rd)
+			if err := validateRoutingUpstreams(rd); err != nil {
+				return err
+			}
+			if rd.Id() != "" && rd.HasChanges("routing", "upstreams") {
+				return rd.SetNewComputed("upstreams_status")
+			}
+			return nil
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -610,6 +660,17 @@ func resourceApimFlexGatewayRead(ctx context.Context, d *schema.ResourceData, m
 		})
 		return diags
 	}
+	if routings, ok := details["routing"].([]map[string]interface{}); ok {
+		status := flattenApimFlexGatewayUpstreamsStatus(d.Get("upstreams").([]interface{}), routings)
+		if err := d.Set("upstreams_status", status); err != nil {
+			diags := append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set API manager's flex gateway instance upstreams status",
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
 
 	d.SetId(id)
 	d.Set("env_id", envid)
@@ -1205,11 +1266,19 @@ func mergeRoutingDetails2ResourceData(d *schema.ResourceData, data []map[string]
 	return result, nil
 }
 
-// validates if routing have a declared upstream
+/*
+Validates the routing of the instance during the plan:
+  - each route references declared upstreams and its upstreams weights add up to 100
+  - routes don't overlap, a route is unreachable when a previous route matches all of its requests
+  - every declared upstream is used by at least one route, unless allow_unused_upstreams is set (i.e. staged upstreams)
+*/
 func validateRoutingUpstreams(d *schema.ResourceDiff) error {
 	var upstreams []interface{}
 	var routings []interface{}
 	weight_limit := 100
+	if !d.NewValueKnown("routing") || !d.NewValueKnown("upstreams") {
+		return nil
+	}
 	if val, ok := d.GetOk("upstreams"); ok {
 		upstreams = val.([]interface{})
 	}
@@ -1218,6 +1287,7 @@ func validateRoutingUpstreams(d *schema.ResourceDiff) error {
 	} else {
 		return nil
 	}
+	used := make(map[string]bool)
 	for _, routing_item := range routings {
 		ritem := routing_item.(map[string]interface{})
 		label := ritem["label"]
@@ -1232,6 +1302,11 @@ func validateRoutingUpstreams(d *schema.ResourceDiff) error {
 					if len(filtered) == 0 {
 						return fmt.Errorf("could not find upstream with label %s for routing %s in your list of upstreams", val.(string), label.(string))
 					}
+					if used[label.(string)+"/"+val.(string)] {
+						return fmt.Errorf("upstream %s is used more than once in routing \"%s\"", val.(string), label.(string))
+					}
+					used[label.(string)+"/"+val.(string)] = true
+					used[val.(string)] = true
 				} else {
 					return fmt.Errorf("routings label is mandatory ")
 				}
@@ -1245,9 +1320,170 @@ func validateRoutingUpstreams(d *schema.ResourceDiff) error {
 			}
 		}
 	}
+	if err := validateRoutingRules(routings); err != nil {
+		return err
+	}
+	if d.Get("allow_unused_upstreams").(bool) {
+		return nil
+	}
+	for _, upstream_item := range upstreams {
+		label := upstream_item.(map[string]interface{})["label"].(string)
+		if !used[label] {
+			return fmt.Errorf("upstream %s is not used by any routing, reference it in a routing or set allow_unused_upstreams for staged upstreams", label)
+		}
+	}
 	return nil
 }
 
+// validates that the routes don't overlap, routes are matched in order by the gateway.
+func validateRoutingRules(routings []interface{}) error {
+	rules := make([]map[string]interface{}, len(routings))
+	for i, routing_item := range routings {
+		rules[i] = getRoutingRules(routing_item.(map[string]interface{}))
+	}
+	for j := range routings {
+		jlabel := routings[j].(map[string]interface{})["label"].(string)
+		for i := 0; i < j; i++ {
+			ilabel := routings[i].(map[string]interface{})["label"].(string)
+			if isRoutingRulesShadowing(rules[i], rules[j]) {
+				return fmt.Errorf("routing \"%s\" is unreachable, all of its requests are matched by the previous routing \"%s\"", jlabel, ilabel)
+			}
+			if isRoutingRulesOverlapping(rules[i], rules[j]) {
+				return fmt.Errorf("routing \"%s\" overlaps with routing \"%s\", they match the same path, host, headers and methods", jlabel, ilabel)
+			}
+		}
+	}
+	return nil
+}
+
+// returns the rules of the given routing, an empty map if the routing doesn't define rules
+func getRoutingRules(routing map[string]interface{}) map[string]interface{} {
+	if val, ok := routing["rules"]; ok && val != nil {
+		list := val.(*schema.Set).List()
+		if len(list) > 0 && list[0] != nil {
+			return list[0].(map[string]interface{})
+		}
+	}
+	return map[string]interface{}{}
+}
+
+// returns true if every request matched by rules b is matched by the previous rules a
+func isRoutingRulesShadowing(a, b map[string]interface{}) bool {
+	if !isRoutingRulesMatchingSameRequests(a, b) {
+		return false
+	}
+	amethods := getRoutingRulesMethods(a)
+	bmethods := getRoutingRulesMethods(b)
+	if len(amethods) == 0 {
+		return true
+	}
+	if len(bmethods) == 0 {
+		return false
+	}
+	for _, m := range bmethods {
+		if !StringInSlice(amethods, m, false) {
+			return false
+		}
+	}
+	return true
+}
+
+// returns true if the rules a and b match the same path, host and headers and have methods in common
+func isRoutingRulesOverlapping(a, b map[string]interface{}) bool {
+	if !isRoutingRulesMatchingSameRequests(a, b) {
+		return false
+	}
+	for _, m := range getRoutingRulesMethods(b) {
+		if StringInSlice(getRoutingRulesMethods(a), m, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// returns true if the rules a match at least the requests of rules b regardless of the methods.
+// the headers of a must be a subset of the headers of b.
+func isRoutingRulesMatchingSameRequests(a, b map[string]interface{}) bool {
+	if getRoutingRulesString(a, "path") != getRoutingRulesString(b, "path") && getRoutingRulesString(a, "path") != "" {
+		return false
+	}
+	if getRoutingRulesString(a, "host") != getRoutingRulesString(b, "host") && getRoutingRulesString(a, "host") != "" {
+		return false
+	}
+	aheaders := getRoutingRulesHeaders(a)
+	bheaders := getRoutingRulesHeaders(b)
+	for k, v := range aheaders {
+		if val, ok := bheaders[k]; !ok || val != v {
+			return false
+		}
+	}
+	return true
+}
+
+func getRoutingRulesString(rules map[string]interface{}, attr string) string {
+	if val, ok := rules[attr]; ok && val != nil {
+		return val.(string)
+	}
+	return ""
+}
+
+func getRoutingRulesMethods(rules map[string]interface{}) []string {
+	if val, ok := rules["methods"]; ok && val != nil {
+		return ListInterface2ListStrings(val.(*schema.Set).List())
+	}
+	return []string{}
+}
+
+func getRoutingRulesHeaders(rules map[string]interface{}) map[string]interface{} {
+	if val, ok := rules["headers"]; ok && val != nil {
+		return val.(map[string]interface{})
+	}
+	return map[string]interface{}{}
+}
+
+/*
+Computes the status of each upstream from the upstreams and routing returned by api manager,
+the platform doesn't expose the health of the upstreams:
+  - routed: the upstream receives traffic from at least one route
+  - disabled: the upstream is only referenced with a weight of 0
+  - unused: the upstream is not referenced by any route
+*/
+func flattenApimFlexGatewayUpstreamsStatus(upstreams []interface{}, routings []map[string]interface{}) []interface{} {
+	result := make([]interface{}, len(upstreams))
+	for i, u := range upstreams {
+		upstream := u.(map[string]interface{})
+		id := upstream["id"].(string)
+		status := "unused"
+		routes := make([]string, 0)
+		for _, routing := range routings {
+			rupstreams, ok := routing["upstreams"].([]map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, rupstream := range rupstreams {
+				if rid, ok := rupstream["id"].(string); !ok || rid != id {
+					continue
+				}
+				if label, ok := routing["label"].(string); ok {
+					routes = append(routes, label)
+				}
+				if fmt.Sprint(rupstream["weight"]) != "0" {
+					status = "routed"
+				} else if status == "unused" {
+					status = "disabled"
+				}
+			}
+		}
+		result[i] = map[string]interface{}{
+			"upstream_id": id,
+			"label":       upstream["label"],
+			"status":      status,
+			"routes":      routes,
+		}
+	}
+	return result
+}
+
 func getApimFlexGatewayUpdatableAttributes() []string {
 	attributes := [...]string{
 		"instance_label", "description", "tags", "provider_id",
@@ -1348,6 +1584,6 @@ func isUpstreamTlsContextEqual(a *schema.Set, b *schema.Set) bool {
  * Returns authentication context (includes authorization header)
  */
 func getFlexGatewayAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, flexgateway.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, flexgateway.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, flexgateway.ContextServerIndex, pco.server_index)
 }
@@ -7,6 +7,7 @@ import (
 	"maps"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -17,6 +18,8 @@ import (
 
 const APIM_MULE4_TECHNOLOGY = "mule4"
 
+const APIM_MULE4_PROXY_POLL_INTERVAL = 10 * time.Second
+
 func resourceApimMule4() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceApimMule4Create,
@@ -25,7 +28,13 @@ func resourceApimMule4() *schema.Resource {
 		DeleteContext: resourceApimMule4Delete,
 		Description: `
 		Create and manage an API Manager Instance of type Mule4.
+		The instance is either a basic endpoint, managed by the mule application through autodiscovery,
+		or a proxy deployed by API Manager to a CloudHub 2.0 private space or a Runtime Fabric when ` + "`deployment_target_id`" + ` is set.
+		The proxy is redeployed whenever its deployment or endpoint settings change.
 		`,
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			return validateApimMule4Deployment(rd)
+		},
 		Schema: map[string]*schema.Schema{
 			"last_updated": {
 				Type:        schema.TypeString,
@@ -137,7 +146,7 @@ func resourceApimMule4() *schema.Resource {
 			"endpoint_uri": {
 				Type:             schema.TypeString,
 				Required:         true,
-				Description:      "The endpoint URI of this instance API",
+				Description:      "The endpoint URI of this instance API. For proxies, the implementation URI the requests are forwarded to.",
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPorHTTPS),
 			},
 			"endpoint_audit": {
@@ -158,7 +167,7 @@ func resourceApimMule4() *schema.Resource {
 			"endpoint_proxy_uri": {
 				Type:             schema.TypeString,
 				Optional:         true,
-				Description:      "Endpoint's Proxy URI",
+				Description:      "Endpoint's Proxy URI. Required for proxies, ex: http://0.0.0.0:8081/",
 				ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPorHTTPS),
 			},
 			"endpoint_deployment_type": {
@@ -173,6 +182,97 @@ func resourceApimMule4() *schema.Resource {
 					),
 				),
 			},
+			"deployment_target_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The id of the CloudHub 2.0 private space or Runtime Fabric where the proxy is deployed. The instance is a basic endpoint if not set.",
+			},
+			"deployment_target_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the CloudHub 2.0 private space or Runtime Fabric where the proxy is deployed.",
+			},
+			"deployment_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "CH2",
+				Description: "The type of target where the proxy is deployed. \"CH2\" for CloudHub 2.0 or \"RF\" for Runtime Fabric",
+				ValidateDiagFunc: validation.ToDiagFunc(
+					validation.StringInSlice(
+						[]string{"CH2", "RF"},
+						false,
+					),
+				),
+			},
+			"deployment_gateway_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The mule runtime version of the proxy, ex: 4.6.0",
+			},
+			"deployment_overwrite": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to overwrite an existing application with the same name as the proxy.",
+			},
+			"deployment_expected_status": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "deployed",
+				Description: "The proxy's deployment expected status. \"deployed\" or \"undeployed\"",
+				ValidateDiagFunc: validation.ToDiagFunc(
+					validation.StringInSlice(
+						[]string{"deployed", "undeployed"},
+						false,
+					),
+				),
+			},
+			"deployment_wait": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to wait for the proxy to be running after each deployment.",
+			},
+			"deployment_timeout": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          600,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+				Description:      "The maximum time in seconds to wait for the proxy to be running.",
+			},
+			"deployment_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's deployment id in runtime manager",
+			},
+			"deployment_application_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's application id",
+			},
+			"deployment_application_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's application name",
+			},
+			"deployment_updated_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's deployment update date",
+			},
+			"deployment_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's deployment status in runtime manager, ex: APPLIED or FAILED",
+			},
+			"deployment_application_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The proxy's application status in runtime manager, ex: RUNNING",
+			},
 			// "routing": {
 			// 	Type:        schema.TypeList,
 			// 	Computed:    true,
@@ -226,7 +326,13 @@ func resourceApimMule4Create(ctx context.Context, d *schema.ResourceData, m inte
 	//update ids following the creation
 	id := res.GetId()
 	d.SetId(strconv.Itoa(int(id)))
-
+	//wait for the proxy to be deployed
+	if isApimMule4Proxy(d) {
+		diags = append(diags, waitApimMule4ProxyDeployment(ctx, d, &pco)...)
+		if diags.HasError() {
+			return diags
+		}
+	}
 	//perform read
 	diags = append(diags, resourceApimMule4Read(ctx, d, m)...)
 	return diags
@@ -271,6 +377,19 @@ func resourceApimMule4Read(ctx context.Context, d *schema.ResourceData, m interf
 		})
 		return diags
 	}
+	//read the status of the proxy in runtime manager
+	if deployment, ok := res.GetDeploymentOk(); ok && deployment != nil && deployment.GetDeploymentId() != "" {
+		status, app_status, err := getApimMule4ProxyStatus(ctx, &pco, orgid, envid, deployment.GetDeploymentId())
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Unable to read the status of API manager's mule4 proxy " + id,
+				Detail:   err.Error(),
+			})
+		}
+		d.Set("deployment_status", status)
+		d.Set("deployment_application_status", app_status)
+	}
 
 	d.SetId(id)
 	d.Set("env_id", envid)
@@ -287,7 +406,7 @@ func resourceApimMule4Update(ctx context.Context, d *schema.ResourceData, m inte
 	envid := d.Get("env_id").(string)
 	apimid := d.Get("id").(string)
 
-	if d.HasChanges(getApimMule4UpdatableAttributes()...) {
+	if d.HasChanges(getApimMule4InstanceUpdatableAttributes()...) {
 		body := newApimMule4PatchBody(d)
 		authctx := getApimAuthCtx(ctx, &pco)
 		_, httpr, err := pco.apimclient.DefaultApi.PatchApimInstance(authctx, orgid, envid, apimid).Body(body).Execute()
@@ -308,9 +427,17 @@ func resourceApimMule4Update(ctx context.Context, d *schema.ResourceData, m inte
 			return diags
 		}
 		defer httpr.Body.Close()
-		return resourceApimMule4Read(ctx, d, m)
+		d.Set("last_updated", time.Now().Format(time.RFC850))
 	}
-	return diags
+	// the proxy is redeployed when its deployment or its endpoint change
+	if isApimMule4Proxy(d) && d.HasChanges(getApimMule4RedeployAttributes()...) {
+		diags = append(diags, redeployApimMule4Proxy(ctx, d, &pco)...)
+		if diags.HasError() {
+			return diags
+		}
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return append(diags, resourceApimMule4Read(ctx, d, m)...)
 }
 
 // deletes the api manager instnace mule4
@@ -359,7 +486,11 @@ func newApimMule4PostBody(d *schema.ResourceData) *apim.ApimInstancePostBody {
 	}
 	body.SetTechnology(APIM_MULE4_TECHNOLOGY)
 	body.SetEndpoint(*endpoint)
-	body.SetDeploymentNil()
+	if isApimMule4Proxy(d) {
+		body.SetDeployment(*newApimMule4DeploymentPostBody(d))
+	} else {
+		body.SetDeploymentNil()
+	}
 	body.SetSpec(*spec)
 
 	return body
@@ -369,7 +500,7 @@ func newApimMule4PostBody(d *schema.ResourceData) *apim.ApimInstancePostBody {
 func newApimMule4PatchBody(d *schema.ResourceData) map[string]interface{} {
 	body := make(map[string]interface{})
 	attributes := FilterStrList(getApimMule4UpdatableAttributes(), func(s string) bool {
-		return !strings.HasPrefix(s, "endpoint")
+		return !strings.HasPrefix(s, "endpoint") && !strings.HasPrefix(s, "deployment")
 	})
 	for _, attr := range attributes {
 		if d.HasChange(attr) {
@@ -405,7 +536,9 @@ func getApimMule4DetailsAttributes() []string {
 		"asset_id", "asset_version", "product_version", "description", "tags", "order", "provider_id",
 		"deprecated", "last_active_date", "endpoint_uri", "is_public", "technology", "endpoint_audit",
 		"endpoint_id", "endpoint_type", "endpoint_proxy_uri", "endpoint_deployment_type",
-		"status", "autodiscovery_instance_name",
+		"status", "autodiscovery_instance_name", "deployment_target_id", "deployment_target_name",
+		"deployment_type", "deployment_gateway_version", "deployment_expected_status", "deployment_id",
+		"deployment_application_id", "deployment_application_name", "deployment_updated_date",
 	}
 	return attributes[:]
 }
@@ -414,6 +547,159 @@ func getApimMule4UpdatableAttributes() []string {
 	attributes := [...]string{
 		"instance_label", "description", "tags", "provider_id",
 		"deprecated", "endpoint_uri", "endpoint_proxy_uri", "endpoint_deployment_type",
+		"deployment_target_name", "deployment_type", "deployment_gateway_version",
+		"deployment_expected_status", "deployment_overwrite",
 	}
 	return attributes[:]
 }
+
+// the attributes of the instance itself, the deployment attributes are applied by redeploying the proxy
+func getApimMule4InstanceUpdatableAttributes() []string {
+	return FilterStrList(getApimMule4UpdatableAttributes(), func(s string) bool {
+		return !strings.HasPrefix(s, "deployment")
+	})
+}
+
+// the attributes requiring the proxy to be redeployed when changed
+func getApimMule4RedeployAttributes() []string {
+	attributes := [...]string{
+		"endpoint_uri", "endpoint_proxy_uri", "deployment_target_name", "deployment_type",
+		"deployment_gateway_version", "deployment_expected_status", "deployment_overwrite",
+	}
+	return attributes[:]
+}
+
+// returns true if the instance is a proxy deployed by api manager
+func isApimMule4Proxy(d *schema.ResourceData) bool {
+	return d.Get("deployment_target_id").(string) != ""
+}
+
+func newApimMule4DeploymentPostBody(d *schema.ResourceData) *apim.DeploymentPostBody {
+	body := apim.NewDeploymentPostBody()
+	body.SetTargetId(d.Get("deployment_target_id").(string))
+	if val, ok := d.GetOk("deployment_target_name"); ok {
+		body.SetTargetName(val.(string))
+	}
+	if val, ok := d.GetOk("deployment_gateway_version"); ok {
+		body.SetGatewayVersion(val.(string))
+	}
+	body.SetType(d.Get("deployment_type").(string))
+	body.SetEnvironmentId(d.Get("env_id").(string))
+	body.SetOverwrite(d.Get("deployment_overwrite").(bool))
+	body.SetExpectedStatus(d.Get("deployment_expected_status").(string))
+	return body
+}
+
+// redeploys the proxy with the current deployment and endpoint settings
+func redeployApimMule4Proxy(ctx context.Context, d *schema.ResourceData, pco *ProviderConfOutput) diag.Diagnostics {
+	var diags diag.Diagnostics
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("id").(string)
+	endpoint, _ := newApimFlexGatewayEndpointPostBody(d).ToMap()
+	deployment, _ := newApimMule4DeploymentPostBody(d).ToMap()
+	// the proxy application already exists, it is overwritten
+	deployment["overwrite"] = true
+	body := map[string]interface{}{
+		"endpoint":   endpoint,
+		"deployment": deployment,
+	}
+	authctx := getApimAuthCtx(ctx, pco)
+	_, httpr, err := pco.apimclient.DefaultApi.PatchApimInstance(authctx, orgid, envid, apimid).Body(body).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to redeploy api manager mule4 proxy " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	return append(diags, waitApimMule4ProxyDeployment(ctx, d, pco)...)
+}
+
+// waits for the proxy to reach its expected status in runtime manager, if enabled
+func waitApimMule4ProxyDeployment(ctx context.Context, d *schema.ResourceData, pco *ProviderConfOutput) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !d.Get("deployment_wait").(bool) || d.Get("deployment_expected_status").(string) != "deployed" {
+		return diags
+	}
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("id").(string)
+	timeout := time.Duration(d.Get("deployment_timeout").(int)) * time.Second
+	if err := waitApimMule4ProxyRunning(ctx, pco, orgid, envid, apimid, timeout); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "The api manager mule4 proxy " + apimid + " is not running",
+			Detail:   err.Error(),
+		})
+	}
+	return diags
+}
+
+// polls the proxy deployment until it is applied and its application is running
+func waitApimMule4ProxyRunning(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	authctx := getApimAuthCtx(ctx, pco)
+	var last error
+	for {
+		res, httpr, err := pco.apimclient.DefaultApi.GetApimInstanceDetails(authctx, orgid, envid, apimid).Execute()
+		if err != nil {
+			last = fmt.Errorf("unable to read instance %s: %s", apimid, readRestClientErrorDetails(httpr, err))
+		} else {
+			httpr.Body.Close()
+			deployment := res.GetDeployment()
+			if id := deployment.GetDeploymentId(); id == "" {
+				last = fmt.Errorf("the proxy of instance %s is not deployed yet", apimid)
+			} else {
+				status, app_status, err := getApimMule4ProxyStatus(ctx, pco, orgid, envid, id)
+				if err != nil {
+					last = err
+				} else if status == "FAILED" {
+					return fmt.Errorf("the deployment %s of proxy %s failed", id, deployment.GetApplicationName())
+				} else if status == "APPLIED" && app_status == "RUNNING" {
+					return nil
+				} else {
+					last = fmt.Errorf("the deployment %s is %s and the proxy is %s", id, status, app_status)
+				}
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout while waiting for the proxy of instance %s to be running, last error: %v", apimid, last)
+		}
+		if err := sleepWithContext(ctx, APIM_MULE4_PROXY_POLL_INTERVAL); err != nil {
+			return err
+		}
+	}
+}
+
+// returns the status of the proxy deployment and of its application in runtime manager
+func getApimMule4ProxyStatus(ctx context.Context, pco *ProviderConfOutput, orgid, envid, deploymentid string) (string, string, error) {
+	authctx := getAppDeploymentV2AuthCtx(ctx, pco)
+	res, httpr, err := pco.appmanagerclient.DefaultApi.GetDeploymentById(authctx, orgid, envid, deploymentid).Execute()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to read deployment %s: %s", deploymentid, readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	application := res.GetApplication()
+	return res.GetStatus(), application.GetStatus(), nil
+}
+
+func validateApimMule4Deployment(d *schema.ResourceDiff) error {
+	if d.Get("deployment_target_id").(string) == "" {
+		return nil
+	}
+	if val, ok := d.GetOk("endpoint_proxy_uri"); !ok || val.(string) == "" {
+		return fmt.Errorf("endpoint_proxy_uri is required when the proxy is deployed by API manager (deployment_target_id is set)")
+	}
+	return nil
+}
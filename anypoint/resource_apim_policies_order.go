@@ -0,0 +1,259 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim_policy"
+)
+
+type apimPolicyOrder struct {
+	Id    int32 `json:"id"`
+	Order int   `json:"order"`
+}
+
+func resourceApimPoliciesOrder() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimPoliciesOrderCreate,
+		ReadContext:   resourceApimPoliciesOrderRead,
+		UpdateContext: resourceApimPoliciesOrderUpdate,
+		DeleteContext: resourceApimPoliciesOrderDelete,
+		Description: `
+		Manages the execution order of the policies of an API Manager instance.
+		The listed policies are executed first, in the given order, followed by the policies not managed by this resource.
+		Reordering the listed policies outside of terraform (i.e. from the UI), or moving any other policy ahead of them, is detected as a drift.
+		NOTE: The order of the policies is left as is when this resource is deleted.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{apim_id}",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api manager instance id where the policies are defined.",
+			},
+			"policy_ids": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The ids of the policies in their execution order.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceApimPoliciesOrderCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	if diags := applyApimPoliciesOrder(ctx, d, m); diags.HasError() {
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid}))
+	return resourceApimPoliciesOrderRead(ctx, d, m)
+}
+
+func resourceApimPoliciesOrderRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	if isComposedResourceId(d.Id()) {
+		orgid, envid, apimid = decomposeApimPoliciesOrderId(d)
+	}
+	policies, diags := getApimPoliciesSortedByOrder(ctx, &pco, orgid, envid, apimid)
+	if diags.HasError() {
+		return diags
+	}
+	managed := ListInterface2ListStrings(d.Get("policy_ids").([]interface{}))
+	ids := getApimPoliciesOrderTrackedIds(managed, policies)
+	if err := d.Set("policy_ids", ids); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set policies order for api " + apimid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("apim_id", apimid)
+	return diags
+}
+
+func resourceApimPoliciesOrderUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("policy_ids") {
+		if diags := applyApimPoliciesOrder(ctx, d, m); diags.HasError() {
+			return diags
+		}
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimPoliciesOrderRead(ctx, d, m)
+}
+
+func resourceApimPoliciesOrderDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	// the platform always keeps an order for the policies, there is nothing to delete
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+// reorders the policies of the api instance, the managed policies first followed by the others in their current order
+func applyApimPoliciesOrder(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	policy_ids := ListInterface2ListStrings(d.Get("policy_ids").([]interface{}))
+	policies, diags := getApimPoliciesSortedByOrder(ctx, &pco, orgid, envid, apimid)
+	if diags.HasError() {
+		return diags
+	}
+	body, err := newApimPoliciesOrderBody(policy_ids, policies)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to order policies for api " + apimid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	authctx := getRestAuthCtx(ctx, &pco)
+	path := fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/environments/%s/apis/%s/policies",
+		url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(apimid),
+	)
+	httpr, err := pco.restclient.Patch(authctx, path, body, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to order policies for api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	return diags
+}
+
+func newApimPoliciesOrderBody(policy_ids []string, policies []apim_policy.ApimPolicy) ([]apimPolicyOrder, error) {
+	existing := make(map[string]int32, len(policies))
+	for _, policy := range policies {
+		existing[strconv.Itoa(int(policy.GetId()))] = policy.GetId()
+	}
+	body := make([]apimPolicyOrder, 0, len(policies))
+	for _, id := range policy_ids {
+		pid, ok := existing[id]
+		if !ok {
+			return nil, fmt.Errorf("policy %s doesn't exist on the api instance", id)
+		}
+		body = append(body, apimPolicyOrder{Id: pid, Order: len(body) + 1})
+		delete(existing, id)
+	}
+	for _, policy := range policies {
+		id := strconv.Itoa(int(policy.GetId()))
+		if _, ok := existing[id]; ok {
+			body = append(body, apimPolicyOrder{Id: policy.GetId(), Order: len(body) + 1})
+		}
+	}
+	return body, nil
+}
+
+// returns the policies of the api instance sorted by their execution order
+func getApimPoliciesSortedByOrder(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid string) ([]apim_policy.ApimPolicy, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	authctx := getApimPolicyAuthCtx(ctx, pco)
+	res, httpr, err := pco.apimpolicyclient.DefaultApi.GetApimPolicies(authctx, orgid, envid, apimid).FullInfo(false).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get policies for api " + apimid,
+			Detail:   details,
+		})
+		return nil, diags
+	}
+	defer httpr.Body.Close()
+	policies := make([]apim_policy.ApimPolicy, 0)
+	if res.ArrayOfApimPolicy != nil {
+		policies = append(policies, *res.ArrayOfApimPolicy...)
+	}
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].GetOrder() < policies[j].GetOrder()
+	})
+	return policies, diags
+}
+
+/*
+Returns the ids of the policies tracked in the state, all the policies are tracked on import.
+The managed policies are expected to be executed first, the ordered policies are therefore tracked
+up to the last managed one, so that any unmanaged policy executed ahead of a managed one shows as a drift.
+*/
+func getApimPoliciesOrderTrackedIds(managed []string, policies []apim_policy.ApimPolicy) []string {
+	ids := make([]string, 0, len(policies))
+	last := len(policies) - 1
+	if len(managed) > 0 {
+		last = -1
+		for i, policy := range policies {
+			if StringInSlice(managed, strconv.Itoa(int(policy.GetId())), false) {
+				last = i
+			}
+		}
+	}
+	for _, policy := range policies[:last+1] {
+		ids = append(ids, strconv.Itoa(int(policy.GetId())))
+	}
+	return ids
+}
+
+func decomposeApimPoliciesOrderId(d *schema.ResourceData) (string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2]
+}
@@ -0,0 +1,602 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim_policy"
+)
+
+func resourceApimInstancePolicyCors() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimInstancePolicyCorsCreate,
+		ReadContext:   resourceApimInstancePolicyCorsRead,
+		UpdateContext: resourceApimInstancePolicyCorsUpdate,
+		DeleteContext: resourceApimInstancePolicyCorsDelete,
+		Description: `
+		Create and manage an API Policy of type cors.
+		Enables cross-origin resource sharing for the API, either for any origin (public resource) or for the configured origin groups.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The policy's unique id",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api manager instance id where the api instance is defined.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"audit": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The instance's auditing data",
+			},
+			"master_organization_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"configuration_data": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Description: "The policy configuration data",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"public_resource": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether the resource is accessible from any origin. Origin groups are ignored for public resources.",
+						},
+						"support_credentials": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Whether the requests can include credentials (cookies, authorization headers or TLS client certificates). Not supported by public resources.",
+						},
+						"origin_groups": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "The groups of origins allowed to access the resource.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"origin_group_name": {
+										Type:        schema.TypeString,
+										Required:    true,
+										Description: "The name of the origin group.",
+									},
+									"origins": {
+										Type:        schema.TypeList,
+										Required:    true,
+										MinItems:    1,
+										Description: "The allowed origins (i.e. https://www.example.com).",
+										Elem: &schema.Schema{
+											Type:             schema.TypeString,
+											ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithHTTPorHTTPS),
+										},
+									},
+									"access_control_max_age": {
+										Type:             schema.TypeInt,
+										Optional:         true,
+										Default:          30,
+										Description:      "The duration in seconds the preflight response can be cached by the client.",
+										ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+									},
+									"allowed_methods": {
+										Type:        schema.TypeSet,
+										Optional:    true,
+										Description: "The HTTP methods allowed for the origins of the group.",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+											ValidateDiagFunc: validation.ToDiagFunc(
+												validation.StringInSlice(APIM_POLICY_CORS_METHODS, false),
+											),
+										},
+									},
+									"headers": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "The headers allowed in the requests.",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"exposed_headers": {
+										Type:        schema.TypeList,
+										Optional:    true,
+										Description: "The response headers exposed to the client.",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"policy_template_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The policy template id",
+			},
+			"order": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The policy order.",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the policy is disabled.",
+			},
+			"pointcut_data": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The Method & resource conditions",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"method_regex": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Description: "The list of HTTP methods",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+								ValidateDiagFunc: validation.ToDiagFunc(
+									validation.StringInSlice(
+										[]string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "TRACE"},
+										false,
+									),
+								),
+							},
+						},
+						"uri_template_regex": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "URI template regex",
+						},
+					},
+				},
+			},
+			"asset_group_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "68ef9520-24e9-4cf2-b2f5-620025690913",
+				Description: "The policy template group id in anypoint exchange. Don't change unless mulesoft has renamed the policy group id.",
+			},
+			"asset_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "cors",
+				Description: "The policy template id in anypoint exchange. Don't change unless mulesoft has renamed the policy asset id.",
+			},
+			"asset_version": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "1.3.2",
+				Description: "the policy template version in anypoint exchange.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			return validateCorsCfg(rd)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourceApimInstancePolicyCorsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	//prepare body
+	body := newApimPolicyCorsBody(d)
+	//perform request
+	res, httpr, err := pco.apimpolicyclient.DefaultApi.PostApimPolicy(authctx, orgid, envid, apimid).ApimPolicyBody(*body).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create policy cors for api " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	id := res.GetId()
+	d.SetId(strconv.Itoa(int(id)))
+	diags = append(diags, resourceApimInstancePolicyCorsRead(ctx, d, m)...)
+	// in case disabled
+	disabled := d.Get("disabled").(bool)
+	if disabled {
+		diags = append(diags, disableApimInstancePolicyCors(ctx, d, m)...)
+		diags = append(diags, resourceApimInstancePolicyCorsRead(ctx, d, m)...)
+	}
+
+	return diags
+}
+
+func resourceApimInstancePolicyCorsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	id := d.Get("id").(string)
+	if isComposedResourceId(id) {
+		orgid, envid, apimid, id = decomposeApimPolicyCorsId(d)
+	}
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	//perform request
+	res, httpr, err := pco.apimpolicyclient.DefaultApi.GetApimPolicy(authctx, orgid, envid, apimid, id).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read policy cors " + id + " for api " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// process data
+	data := flattenApimInstancePolicy(res)
+	data["configuration_data"] = []interface{}{flattenApimPolicyCorsCfg(d, res)}
+	if err := setApimInstancePolicyAttributesToResourceData(d, data); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set api policy cors details attributes",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(id)
+	d.Set("apim_id", apimid)
+	d.Set("env_id", envid)
+	d.Set("org_id", orgid)
+	return diags
+}
+
+func resourceApimInstancePolicyCorsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	//detect change
+	if d.HasChanges("configuration_data", "pointcut_data") {
+		pco := m.(ProviderConfOutput)
+		orgid := d.Get("org_id").(string)
+		envid := d.Get("env_id").(string)
+		apimid := d.Get("apim_id").(string)
+		id := d.Get("id").(string)
+		authctx := getApimPolicyAuthCtx(ctx, &pco)
+		//prepare body
+		body := newApimPolicyCorsPatchBody(d)
+		//perform request
+		_, httpr, err := pco.apimpolicyclient.DefaultApi.PatchApimPolicy(authctx, orgid, envid, apimid, id).Body(body).Execute()
+		if err != nil {
+			var details string
+			if httpr != nil && httpr.StatusCode >= 400 {
+				defer httpr.Body.Close()
+				b, _ := io.ReadAll(httpr.Body)
+				details = string(b)
+			} else {
+				details = err.Error()
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update policy cors for api " + apimid,
+				Detail:   details,
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		diags = append(diags, resourceApimInstancePolicyCorsRead(ctx, d, m)...)
+	}
+	if d.HasChange("disabled") {
+		disabled := d.Get("disabled").(bool)
+		if disabled {
+			diags = append(diags, disableApimInstancePolicyCors(ctx, d, m)...)
+		} else {
+			diags = append(diags, enableApimInstancePolicyCors(ctx, d, m)...)
+		}
+		diags = append(diags, resourceApimInstancePolicyCorsRead(ctx, d, m)...)
+	}
+
+	return diags
+}
+
+func resourceApimInstancePolicyCorsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	id := d.Get("id").(string)
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	httpr, err := pco.apimpolicyclient.DefaultApi.DeleteApimPolicy(authctx, orgid, envid, apimid, id).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete policy cors " + id + " for api " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func enableApimInstancePolicyCors(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	id := d.Get("id").(string)
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	_, httpr, err := pco.apimpolicyclient.DefaultApi.EnableApimPolicy(authctx, orgid, envid, apimid, id).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to enable policy cors " + id + " for api " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	return diags
+}
+
+func disableApimInstancePolicyCors(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	id := d.Get("id").(string)
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	_, httpr, err := pco.apimpolicyclient.DefaultApi.DisableApimPolicy(authctx, orgid, envid, apimid, id).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to disable policy cors " + id + " for api " + apimid,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	return diags
+}
+
+func flattenApimPolicyCorsCfg(_ *schema.ResourceData, policy *apim_policy.ApimPolicy) map[string]interface{} {
+	data := make(map[string]interface{})
+	cfg := policy.GetConfigurationData()
+	data["public_resource"] = cfg["publicResource"]
+	data["support_credentials"] = cfg["supportCredentials"]
+	groups, _ := cfg["originGroups"].([]interface{})
+	result := make([]interface{}, len(groups))
+	for i, item := range groups {
+		group := item.(map[string]interface{})
+		g := make(map[string]interface{})
+		g["origin_group_name"] = group["originGroupName"]
+		g["origins"] = group["origins"]
+		g["access_control_max_age"] = group["accessControlMaxAge"]
+		g["headers"] = group["headers"]
+		g["exposed_headers"] = group["exposedHeaders"]
+		// only the allowed methods are kept
+		methods, _ := group["allowedMethods"].([]interface{})
+		allowed := make([]interface{}, 0, len(methods))
+		for _, m := range methods {
+			method := m.(map[string]interface{})
+			if is_allowed, ok := method["isAllowed"].(bool); ok && is_allowed {
+				allowed = append(allowed, method["methodName"])
+			}
+		}
+		g["allowed_methods"] = allowed
+		result[i] = g
+	}
+	data["origin_groups"] = result
+	return data
+}
+
+func newApimPolicyCorsBody(d *schema.ResourceData) *apim_policy.ApimPolicyBody {
+	body := apim_policy.NewApimPolicyBodyWithDefaults()
+	if val, ok := d.GetOk("configuration_data"); ok {
+		l := val.([]interface{})
+		cfg := l[0].(map[string]interface{})
+		data := newApimPolicyCorsCfg(cfg)
+		body.SetConfigurationData(data)
+	}
+	if val, ok := d.GetOk("pointcut_data"); ok {
+		body.SetPointcutData(newApimPolicyCorsPointcutDataBody(val.([]interface{})))
+	}
+	if val, ok := d.GetOk("asset_group_id"); ok {
+		body.SetGroupId(val.(string))
+	}
+	if val, ok := d.GetOk("asset_id"); ok {
+		body.SetAssetId(val.(string))
+	}
+	if val, ok := d.GetOk("asset_version"); ok {
+		body.SetAssetVersion(val.(string))
+	}
+	return body
+}
+
+func newApimPolicyCorsPatchBody(d *schema.ResourceData) map[string]interface{} {
+	body := make(map[string]interface{})
+	if val, ok := d.GetOk("configuration_data"); ok {
+		l := val.([]interface{})
+		cfg := l[0].(map[string]interface{})
+		data := newApimPolicyCorsCfg(cfg)
+		body["configurationData"] = data
+	}
+	if val, ok := d.GetOk("pointcut_data"); ok {
+		collection := newApimPolicyCorsPointcutDataBody(val.([]interface{}))
+		slice := make([]map[string]interface{}, len(collection))
+		for i, item := range collection {
+			m, _ := item.ToMap()
+			slice[i] = m
+		}
+		body["pointcutData"] = slice
+	} else {
+		body["pointcutData"] = nil
+	}
+	if val, ok := d.GetOk("asset_group_id"); ok {
+		body["groupId"] = val
+	}
+	if val, ok := d.GetOk("asset_id"); ok {
+		body["assetId"] = val
+	}
+	if val, ok := d.GetOk("asset_version"); ok {
+		body["assetVersion"] = val
+	}
+	return body
+}
+
+var APIM_POLICY_CORS_METHODS = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD", "TRACE", "CONNECT"}
+
+func newApimPolicyCorsCfg(input map[string]interface{}) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["publicResource"] = input["public_resource"]
+	body["supportCredentials"] = input["support_credentials"]
+	groups := input["origin_groups"].([]interface{})
+	result := make([]map[string]interface{}, len(groups))
+	for i, item := range groups {
+		group := item.(map[string]interface{})
+		g := make(map[string]interface{})
+		g["originGroupName"] = group["origin_group_name"]
+		g["origins"] = group["origins"]
+		g["accessControlMaxAge"] = group["access_control_max_age"]
+		g["headers"] = group["headers"]
+		g["exposedHeaders"] = group["exposed_headers"]
+		// every method is listed with a flag
+		set := group["allowed_methods"].(*schema.Set)
+		methods := make([]map[string]interface{}, len(APIM_POLICY_CORS_METHODS))
+		for j, method := range APIM_POLICY_CORS_METHODS {
+			methods[j] = map[string]interface{}{
+				"methodName": method,
+				"isAllowed":  set.Contains(method),
+			}
+		}
+		g["allowedMethods"] = methods
+		result[i] = g
+	}
+	body["originGroups"] = result
+	return body
+}
+
+func newApimPolicyCorsPointcutDataBody(collection []interface{}) []apim_policy.PointcutDataItem {
+	slice := make([]apim_policy.PointcutDataItem, len(collection))
+	for i, item := range collection {
+		data := item.(map[string]interface{})
+		body := apim_policy.NewPointcutDataItem()
+		if val, ok := data["method_regex"]; ok && val != nil {
+			set := val.(*schema.Set)
+			body.SetMethodRegex(JoinStringInterfaceSlice(set.List(), "|"))
+		}
+		if val, ok := data["uri_template_regex"]; ok {
+			body.SetUriTemplateRegex(val.(string))
+		}
+		slice[i] = *body
+	}
+	return slice
+}
+
+func validateCorsCfg(d *schema.ResourceDiff) error {
+	c := d.Get("configuration_data")
+	l := c.([]interface{})
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+	cfg := l[0].(map[string]interface{})
+	public_resource := cfg["public_resource"].(bool)
+	support_credentials := cfg["support_credentials"].(bool)
+	groups := cfg["origin_groups"].([]interface{})
+	if public_resource && support_credentials {
+		return fmt.Errorf("attribute support_credentials can't be enabled for public resources")
+	}
+	if !public_resource && len(groups) == 0 {
+		return fmt.Errorf("attribute origin_groups is required when public_resource is false")
+	}
+	return nil
+}
+
+func decomposeApimPolicyCorsId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
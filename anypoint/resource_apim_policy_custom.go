@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"maps"
+	"log"
+	"math"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -21,7 +24,13 @@ func resourceApimInstancePolicyCustom() *schema.Resource {
 		UpdateContext: resourceApimInstancePolicyCustomUpdate,
 		DeleteContext: resourceApimInstancePolicyCustomDelete,
 		Description: `
-		Create and manage an API Policy of any type.
+		Create and manage an API Policy of any type, for mule4 and flex gateway api instances.
+		The configuration data is validated during the plan against the configuration schema of the policy template
+		(required properties, types and allowed values).
+		The policy template is checked against the ` + "`technology`" + ` of the api instance during the plan,
+		which allows to apply flex gateway only policies (ex: tracing, header transformation, A2A & MCP policies) safely.
+		When ` + "`asset_version`" + ` is not set, the latest version of the template applicable to the api instance is used,
+		as listed by the ` + "`anypoint_exchange_policy_templates`" + ` data source.
 		`,
 		Schema: map[string]*schema.Schema{
 			"last_updated": {
@@ -66,8 +75,9 @@ func resourceApimInstancePolicyCustom() *schema.Resource {
 			"configuration_data": {
 				Type:             schema.TypeString,
 				Required:         true,
-				Description:      "The policy configuration data in json format",
+				Description:      "The policy configuration data in json format. It is validated against the configuration schema of the policy template. The json documents are compared semantically and the properties defaulted by the platform to the template's default values are ignored.",
 				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsJSON),
+				DiffSuppressFunc: diffSuppressApimPolicyCustomCfg,
 			},
 			"policy_template_id": {
 				Type:        schema.TypeString,
@@ -127,10 +137,30 @@ func resourceApimInstancePolicyCustom() *schema.Resource {
 			},
 			"asset_version": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
-				Description: "the policy template version in anypoint exchange.",
+				Description: "the policy template version in anypoint exchange. Defaults to the latest version of the template applicable to the api instance.",
 			},
+			"technology": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				ValidateDiagFunc: validation.ToDiagFunc(
+					validation.StringInSlice([]string{APIM_MULE4_TECHNOLOGY, FLEX_GATEWAY_TECHNOLOGY}, false),
+				),
+				Description: `
+				The technology of the api instance the policy is applied to. Supported values are ` + "`" + APIM_MULE4_TECHNOLOGY + "` and `" + FLEX_GATEWAY_TECHNOLOGY + "`" + `.
+				Defaults to the technology of the api instance. When set, the plan fails if the api instance has a different technology.
+				`,
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			if err := validateApimPolicyCustomTechnology(ctx, rd, i); err != nil {
+				return err
+			}
+			return validateApimPolicyCustomCfg(ctx, rd, i)
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -155,6 +185,18 @@ func resourceApimInstancePolicyCustomCreate(ctx context.Context, d *schema.Resou
 		})
 		return diags
 	}
+	if body.GetAssetVersion() == "" {
+		version, err := getApimPolicyCustomLatestTemplateVersion(ctx, &pco, d)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to resolve the policy template version for api " + apimid,
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+		body.SetAssetVersion(version)
+	}
 	//perform request
 	res, httpr, err := pco.apimpolicyclient.DefaultApi.PostApimPolicy(authctx, orgid, envid, apimid).ApimPolicyBody(*body).Execute()
 	if err != nil {
@@ -219,7 +261,10 @@ func resourceApimInstancePolicyCustomRead(ctx context.Context, d *schema.Resourc
 	defer httpr.Body.Close()
 	// process data
 	data := flattenApimInstancePolicy(res)
-	if cfg, err := flattenApimPolicyCustomCfg(d, res); err != nil {
+	defaults := func() map[string]interface{} {
+		return getApimPolicyCustomTemplateDefaults(ctx, &pco, orgid, res.GetGroupId(), res.GetAssetId(), res.GetAssetVersion())
+	}
+	if cfg, err := flattenApimPolicyCustomCfg(d, res, defaults); err != nil {
 		diags = append(diags, diag.Diagnostic{
 			Severity: diag.Error,
 			Summary:  "Unable to parse configuration data of custom policy " + id + " for api " + apimid,
@@ -237,6 +282,13 @@ func resourceApimInstancePolicyCustomRead(ctx context.Context, d *schema.Resourc
 		})
 		return diags
 	}
+	if _, ok := d.GetOk("technology"); !ok {
+		if technology, err := getApimPolicyCustomInstanceTechnology(ctx, &pco, orgid, envid, apimid); err == nil {
+			d.Set("technology", technology)
+		} else {
+			log.Printf("[WARN] Unable to get the technology of api %s: %s\n", apimid, err.Error())
+		}
+	}
 	d.SetId(id)
 	d.Set("apim_id", apimid)
 	d.Set("env_id", envid)
@@ -388,18 +440,100 @@ func disableApimInstancePolicyCustom(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
-func flattenApimPolicyCustomCfg(d *schema.ResourceData, policy *apim_policy.ApimPolicy) (string, error) {
+/*
+Returns the normalized json representation of the policy configuration.
+The properties that aren't set locally and whose values match the template's default values are dropped,
+as they are injected by the platform. The default values are loaded using getDefaults only when needed.
+*/
+func flattenApimPolicyCustomCfg(d *schema.ResourceData, policy *apim_policy.ApimPolicy, getDefaults func() map[string]interface{}) (string, error) {
 	data := policy.GetConfigurationData()
-	var dst map[string]interface{}
-	err := json.Unmarshal([]byte(d.Get("configuration_data").(string)), &dst)
+	local := make(map[string]interface{})
+	if val := d.Get("configuration_data").(string); val != "" {
+		if err := json.Unmarshal([]byte(val), &local); err != nil {
+			return "", fmt.Errorf("configuration_data expected to be a valid JSON Object. %s", err.Error())
+		}
+	}
+	// the template is only fetched when a property isn't set locally
+	var defaults map[string]interface{}
+	result := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if _, ok := local[k]; !ok {
+			if defaults == nil {
+				defaults = getDefaults()
+			}
+			if def, ok := defaults[k]; ok && isApimPolicyCustomCfgValueEqual(def, v) {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	b, err := json.Marshal(result)
 	if err != nil {
-		return "", fmt.Errorf("configuration_data expected to be a valid JSON Object. %s", err.Error())
+		return "", err
 	}
-	maps.Copy(dst, data)
-	b, _ := json.Marshal(data)
 	return string(b), nil
 }
 
+// compares the json documents semantically, ignoring whitespaces, keys order and numbers formatting
+func diffSuppressApimPolicyCustomCfg(k, old, new string, d *schema.ResourceData) bool {
+	var o, n interface{}
+	if err := json.Unmarshal([]byte(old), &o); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &n); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
+
+// default values may be returned as strings by the template (i.e. "10" for an int property)
+func isApimPolicyCustomCfgValueEqual(def interface{}, val interface{}) bool {
+	if reflect.DeepEqual(def, val) {
+		return true
+	}
+	return formatApimPolicyCustomCfgValue(def) == formatApimPolicyCustomCfgValue(val)
+}
+
+/*
+Returns the default values of the policy template properties indexed by property name.
+The default values aren't part of the policy template client model, they are decoded from the raw body of the response.
+An empty map is returned when the template can't be fetched.
+*/
+func getApimPolicyCustomTemplateDefaults(ctx context.Context, pco *ProviderConfOutput, orgid, groupid, assetid, version string) map[string]interface{} {
+	defaults := make(map[string]interface{})
+	authctx := getApimPolicyAuthCtx(ctx, pco)
+	_, httpr, err := pco.apimpolicyclient.DefaultApi.GetOrgExchangePolicyTemplateDetails(authctx, orgid, groupid, assetid, version).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		log.Printf("[WARN] Unable to get policy template %s/%s/%s default values: %s\n", groupid, assetid, version, details)
+		return defaults
+	}
+	defer httpr.Body.Close()
+	var template struct {
+		Configuration []struct {
+			PropertyName string      `json:"propertyName"`
+			DefaultValue interface{} `json:"defaultValue"`
+		} `json:"configuration"`
+	}
+	if err := json.NewDecoder(httpr.Body).Decode(&template); err != nil {
+		log.Printf("[WARN] Unable to parse policy template %s/%s/%s default values: %s\n", groupid, assetid, version, err)
+		return defaults
+	}
+	for _, prop := range template.Configuration {
+		if prop.PropertyName != "" && prop.DefaultValue != nil {
+			defaults[prop.PropertyName] = prop.DefaultValue
+		}
+	}
+	return defaults
+}
+
 func newApimPolicyCustomBody(d *schema.ResourceData) (*apim_policy.ApimPolicyBody, error) {
 	body := apim_policy.NewApimPolicyBody()
 	if val, ok := d.GetOk("configuration_data"); ok {
@@ -475,6 +609,311 @@ func newApimPolicyCustomPointcutDataBody(collection []interface{}) []apim_policy
 	return slice
 }
 
+/*
+Validates the policy template against the technology of the api instance and resolves the template version when not set.
+The instance technology and the templates applicable to the instance are fetched from api manager,
+the validation is skipped when the values are not known yet or when the api instance can't be fetched.
+*/
+func validateApimPolicyCustomTechnology(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	attributes := []string{"org_id", "env_id", "apim_id", "asset_group_id", "asset_id", "asset_version", "technology"}
+	for _, attr := range []string{"org_id", "env_id", "apim_id", "asset_group_id", "asset_id"} {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	if d.Id() != "" && !d.HasChanges(attributes...) {
+		return nil
+	}
+	pco, ok := m.(ProviderConfOutput)
+	if !ok {
+		return nil
+	}
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	groupid := d.Get("asset_group_id").(string)
+	assetid := d.Get("asset_id").(string)
+	technology, err := getApimPolicyCustomInstanceTechnology(ctx, &pco, orgid, envid, apimid)
+	if err != nil {
+		log.Printf("[WARN] Unable to get the technology of api %s, skipping policy template validation: %s\n", apimid, err.Error())
+		return nil
+	}
+	if val, ok := d.GetOk("technology"); ok && d.NewValueKnown("technology") && val.(string) != technology {
+		return fmt.Errorf("technology %s doesn't match the technology of api %s which is %s", val, apimid, technology)
+	}
+	if err := d.SetNew("technology", technology); err != nil {
+		return err
+	}
+	//look up the template within the templates applicable to the instance
+	templates, err := getApimPolicyCustomInstanceTemplates(ctx, &pco, orgid, envid, apimid)
+	if err != nil {
+		log.Printf("[WARN] Unable to get the policy templates of api %s, skipping policy template validation: %s\n", apimid, err.Error())
+		return nil
+	}
+	template := findApimPolicyCustomTemplate(templates, groupid, assetid)
+	if template == nil || !template.GetApplicable() {
+		return fmt.Errorf(
+			"policy template %s/%s can't be applied to api %s: the template is not available for %s api instances",
+			groupid, assetid, apimid, technology,
+		)
+	}
+	if !d.NewValueKnown("asset_version") {
+		return nil
+	}
+	version, ok := d.GetOk("asset_version")
+	if !ok {
+		return d.SetNew("asset_version", template.GetVersion())
+	}
+	if !isApimPolicyCustomTemplateVersion(template, version.(string)) {
+		return fmt.Errorf(
+			"version %s of policy template %s/%s is not available for %s api instances, latest applicable version is %s",
+			version, groupid, assetid, technology, template.GetVersion(),
+		)
+	}
+	return nil
+}
+
+func getApimPolicyCustomInstanceTechnology(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid string) (string, error) {
+	authctx := getApimAuthCtx(ctx, pco)
+	res, httpr, err := pco.apimclient.DefaultApi.GetApimInstanceDetails(authctx, orgid, envid, apimid).Execute()
+	if err != nil {
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			return "", fmt.Errorf("%s", string(b))
+		}
+		return "", err
+	}
+	defer httpr.Body.Close()
+	return res.GetTechnology(), nil
+}
+
+// returns the latest version of the policy templates applicable to the given api instance
+func getApimPolicyCustomInstanceTemplates(ctx context.Context, pco *ProviderConfOutput, orgid, envid, apimid string) ([]apim_policy.ExchangePolicyTemplate, error) {
+	authctx := getApimPolicyAuthCtx(ctx, pco)
+	templates, httpr, err := pco.apimpolicyclient.DefaultApi.GetOrgExchangePolicyTemplates(authctx, orgid).EnvironmentId(envid).ApiInstanceId(apimid).Latest(true).Execute()
+	if err != nil {
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			return nil, fmt.Errorf("%s", string(b))
+		}
+		return nil, err
+	}
+	defer httpr.Body.Close()
+	return templates, nil
+}
+
+/*
+Returns the latest version of the policy template applicable to the api instance.
+The version is resolved during the plan unless the api instance isn't known yet, it is then resolved on creation.
+*/
+func getApimPolicyCustomLatestTemplateVersion(ctx context.Context, pco *ProviderConfOutput, d *schema.ResourceData) (string, error) {
+	apimid := d.Get("apim_id").(string)
+	groupid := d.Get("asset_group_id").(string)
+	assetid := d.Get("asset_id").(string)
+	templates, err := getApimPolicyCustomInstanceTemplates(ctx, pco, d.Get("org_id").(string), d.Get("env_id").(string), apimid)
+	if err != nil {
+		return "", err
+	}
+	template := findApimPolicyCustomTemplate(templates, groupid, assetid)
+	if template == nil || !template.GetApplicable() {
+		return "", fmt.Errorf("policy template %s/%s can't be applied to api %s", groupid, assetid, apimid)
+	}
+	return template.GetVersion(), nil
+}
+
+func findApimPolicyCustomTemplate(templates []apim_policy.ExchangePolicyTemplate, groupid, assetid string) *apim_policy.ExchangePolicyTemplate {
+	for i, template := range templates {
+		if template.GetGroupId() == groupid && template.GetAssetId() == assetid {
+			return &templates[i]
+		}
+	}
+	return nil
+}
+
+// returns true if the given version is the template's version or one of its other versions.
+// the version is not checked when the template doesn't list its versions.
+func isApimPolicyCustomTemplateVersion(template *apim_policy.ExchangePolicyTemplate, version string) bool {
+	versions := template.GetAllVersions()
+	if template.GetVersion() == version || len(versions) == 0 {
+		return true
+	}
+	for _, v := range versions {
+		if v.GetVersion() == version {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+Validates the configuration data against the configuration schema of the policy template.
+The validation is skipped when the values are not known yet or when the template can't be fetched.
+*/
+func validateApimPolicyCustomCfg(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	attributes := []string{"org_id", "configuration_data", "asset_group_id", "asset_id", "asset_version"}
+	for _, attr := range attributes {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	if !d.HasChanges(attributes...) {
+		return nil
+	}
+	pco, ok := m.(ProviderConfOutput)
+	if !ok {
+		return nil
+	}
+	orgid := d.Get("org_id").(string)
+	groupid := d.Get("asset_group_id").(string)
+	assetid := d.Get("asset_id").(string)
+	version := d.Get("asset_version").(string)
+	var cfg map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("configuration_data").(string)), &cfg); err != nil {
+		return fmt.Errorf("configuration_data expected to be a valid JSON Object. %s", err.Error())
+	}
+	authctx := getApimPolicyAuthCtx(ctx, &pco)
+	template, httpr, err := pco.apimpolicyclient.DefaultApi.GetOrgExchangePolicyTemplateDetails(authctx, orgid, groupid, assetid, version).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		log.Printf("[WARN] Unable to get policy template %s/%s/%s, skipping configuration_data validation: %s\n", groupid, assetid, version, details)
+		return nil
+	}
+	defer httpr.Body.Close()
+	errs := validateApimPolicyCustomCfgProperties("configuration_data", template.GetConfiguration(), cfg)
+	if len(errs) > 0 {
+		return fmt.Errorf(
+			"configuration_data doesn't match the configuration schema of policy template %s/%s/%s:\n\t- %s",
+			groupid, assetid, version, strings.Join(errs, "\n\t- "),
+		)
+	}
+	return nil
+}
+
+func validateApimPolicyCustomCfgProperties(path string, props []apim_policy.PolicyConfiguration, cfg map[string]interface{}) []string {
+	errs := make([]string, 0)
+	for _, prop := range props {
+		name := prop.GetPropertyName()
+		if name == "" {
+			continue
+		}
+		attr := path + "." + name
+		val, ok := cfg[name]
+		if !ok || val == nil {
+			if !prop.GetOptional() {
+				errs = append(errs, fmt.Sprintf("%s: the property is required", attr))
+			}
+			continue
+		}
+		if !prop.GetAllowMultiple() {
+			errs = append(errs, validateApimPolicyCustomCfgValue(attr, &prop, val)...)
+			continue
+		}
+		items, ok := val.([]interface{})
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: expected a list of %s values, got %s", attr, prop.GetType(), getApimPolicyCustomCfgValueType(val)))
+			continue
+		}
+		for i, item := range items {
+			errs = append(errs, validateApimPolicyCustomCfgValue(fmt.Sprintf("%s[%d]", attr, i), &prop, item)...)
+		}
+	}
+	return errs
+}
+
+func validateApimPolicyCustomCfgValue(attr string, prop *apim_policy.PolicyConfiguration, val interface{}) []string {
+	errs := make([]string, 0)
+	if !isApimPolicyCustomCfgValueOfType(prop.GetType(), val) {
+		errs = append(errs, fmt.Sprintf("%s: expected a value of type %s, got %s", attr, prop.GetType(), getApimPolicyCustomCfgValueType(val)))
+		return errs
+	}
+	if options := prop.GetOptions(); len(options) > 0 {
+		allowed := make([]string, 0, len(options))
+		for _, opt := range options {
+			if v, ok := opt["value"]; ok {
+				allowed = append(allowed, formatApimPolicyCustomCfgValue(v))
+			}
+		}
+		if len(allowed) > 0 && !StringInSlice(allowed, formatApimPolicyCustomCfgValue(val), false) {
+			errs = append(errs, fmt.Sprintf("%s: expected one of [%s], got %s", attr, strings.Join(allowed, ", "), formatApimPolicyCustomCfgValue(val)))
+		}
+	}
+	// nested properties of key/value like types
+	if nested := prop.GetConfiguration(); len(nested) > 0 {
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Sprintf("%s: expected an object, got %s", attr, getApimPolicyCustomCfgValueType(val)))
+			return errs
+		}
+		for _, inner := range nested {
+			name := inner.GetPropertyName()
+			if v, ok := obj[name]; ok && v != nil && !isApimPolicyCustomCfgValueOfType(inner.GetType(), v) {
+				errs = append(errs, fmt.Sprintf("%s.%s: expected a value of type %s, got %s", attr, name, inner.GetType(), getApimPolicyCustomCfgValueType(v)))
+			}
+		}
+	}
+	return errs
+}
+
+// checks the json value against the policy template property type, unknown types are accepted
+func isApimPolicyCustomCfgValueOfType(ptype string, val interface{}) bool {
+	switch strings.ToLower(ptype) {
+	case "int", "integer", "long":
+		n, ok := val.(float64)
+		return ok && n == math.Trunc(n)
+	case "number", "double", "float":
+		_, ok := val.(float64)
+		return ok
+	case "boolean", "bool":
+		_, ok := val.(bool)
+		return ok
+	case "string", "expression", "dataweave", "radio", "select", "iprange", "url", "text", "password":
+		_, ok := val.(string)
+		return ok
+	case "keyvalue", "keyvalues", "object":
+		_, ok := val.(map[string]interface{})
+		return ok
+	default:
+		return true
+	}
+}
+
+func getApimPolicyCustomCfgValueType(val interface{}) string {
+	switch val.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", val)
+	}
+}
+
+// returns strings as is and the json representation of any other value
+func formatApimPolicyCustomCfgValue(val interface{}) string {
+	if s, ok := val.(string); ok {
+		return s
+	}
+	b, _ := json.Marshal(val)
+	return string(b)
+}
+
 func decomposeApimPolicyCustomId(d *schema.ResourceData) (string, string, string, string) {
 	s := DecomposeResourceId(d.Id())
 	return s[0], s[1], s[2], s[3]
@@ -0,0 +1,365 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type apimPromotionBody struct {
+	Promote       apimPromotion `json:"promote"`
+	InstanceLabel *string       `json:"instanceLabel,omitempty"`
+}
+
+type apimPromotion struct {
+	OriginApiId int                 `json:"originApiId"`
+	Policies    apimPromotionEntity `json:"policies"`
+	Tiers       apimPromotionEntity `json:"tiers"`
+	Alerts      apimPromotionEntity `json:"alerts"`
+}
+
+type apimPromotionEntity struct {
+	AllEntities bool `json:"allEntities"`
+}
+
+type apimPromotionResult struct {
+	Id int `json:"id"`
+}
+
+func resourceApimPromotion() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimPromotionCreate,
+		ReadContext:   resourceApimPromotionRead,
+		UpdateContext: resourceApimPromotionUpdate,
+		DeleteContext: resourceApimPromotionDelete,
+		Description: `
+		Promotes an API Manager instance from a source environment to a target environment, optionally with its policies, SLA tiers and alerts.
+		The promoted instance is created in the target environment and deleted when this resource is destroyed.
+		If the promoted instance is deleted outside of terraform, the promotion is performed again.
+		NOTE: This resource can't be imported as the source of a promoted instance is not kept by API Manager.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{apim_id}",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instances are defined.",
+			},
+			"source_env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id of the api instance to promote, the instance is checked to exist in this environment before the promotion.",
+			},
+			"source_apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the api instance to promote.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where the api instance is promoted.",
+			},
+			"include_policies": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Whether to promote the policies of the api instance.",
+			},
+			"include_tiers": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Whether to promote the SLA tiers of the api instance.",
+			},
+			"include_alerts": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Whether to promote the alerts of the api instance.",
+			},
+			"instance_label": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The label of the promoted instance. Defaults to the label of the source instance.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the promoted api instance.",
+			},
+			"technology": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the promoted api instance.",
+			},
+			"asset_group_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The API specification's business group id",
+			},
+			"asset_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The API specification's asset id in exchange",
+			},
+			"asset_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The API specification's version number in exchange",
+			},
+			"endpoint_uri": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The endpoint URI of the promoted api instance",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The promoted api instance status",
+			},
+			"autodiscovery_instance_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The promoted instance's discovery name",
+			},
+		},
+	}
+}
+
+func resourceApimPromotionCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	source := d.Get("source_apim_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//prepare body
+	body, err := newApimPromotionBody(d)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to parse promotion of api " + source,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	//check the source instance belongs to the source environment
+	if err := checkApimPromotionSource(ctx, &pco, orgid, d.Get("source_env_id").(string), source); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to promote api " + source + " to env " + envid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	//perform request
+	var res apimPromotionResult
+	path := fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/environments/%s/apis",
+		url.PathEscape(orgid), url.PathEscape(envid),
+	)
+	httpr, err := pco.restclient.Post(authctx, path, body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to promote api " + source + " to env " + envid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, envid, strconv.Itoa(res.Id)}))
+	return resourceApimPromotionRead(ctx, d, m)
+}
+
+func resourceApimPromotionRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, id := decomposeApimPromotionId(d)
+	authctx := getApimAuthCtx(ctx, &pco)
+	//perform request
+	res, httpr, err := pco.apimclient.DefaultApi.GetApimInstanceDetails(authctx, orgid, envid, id).Execute()
+	if err != nil {
+		// the promoted instance doesn't exist anymore, the promotion will be performed again
+		if httpr != nil && httpr.StatusCode == http.StatusNotFound {
+			log.Printf("[WARN] promoted api instance %s not found in env %s, removing it from the state", id, envid)
+			d.SetId("")
+			return diags
+		}
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get promoted api instance " + id,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	details := flattenApimInstanceDetails(res)
+	for _, attr := range getApimPromotionAttributes() {
+		if val, ok := details[attr]; ok {
+			if err := d.Set(attr, val); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Error,
+					Summary:  "Unable to set promoted api instance " + id + " details attributes",
+					Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+				})
+				return diags
+			}
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, id}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("apim_id", id)
+	return diags
+}
+
+func resourceApimPromotionUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, id := decomposeApimPromotionId(d)
+	if d.HasChange("instance_label") {
+		body := map[string]interface{}{
+			"instanceLabel": d.Get("instance_label"),
+		}
+		authctx := getApimAuthCtx(ctx, &pco)
+		_, httpr, err := pco.apimclient.DefaultApi.PatchApimInstance(authctx, orgid, envid, id).Body(body).Execute()
+		if err != nil {
+			var details string
+			if httpr != nil && httpr.StatusCode >= 400 {
+				defer httpr.Body.Close()
+				b, _ := io.ReadAll(httpr.Body)
+				details = string(b)
+			} else {
+				details = err.Error()
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update promoted api instance " + id,
+				Detail:   details,
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimPromotionRead(ctx, d, m)
+}
+
+func resourceApimPromotionDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, id := decomposeApimPromotionId(d)
+	authctx := getApimAuthCtx(ctx, &pco)
+	//perform request
+	httpr, err := pco.apimclient.DefaultApi.DeleteApimInstance(authctx, orgid, envid, id).Execute()
+	if err != nil {
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete promoted api instance " + id,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+// returns an error if the source api instance can't be found in the source environment
+func checkApimPromotionSource(ctx context.Context, pco *ProviderConfOutput, orgid, envid, id string) error {
+	authctx := getApimAuthCtx(ctx, pco)
+	_, httpr, err := pco.apimclient.DefaultApi.GetApimInstanceDetails(authctx, orgid, envid, id).Execute()
+	if err != nil {
+		if httpr != nil && httpr.StatusCode == http.StatusNotFound {
+			return fmt.Errorf("the api instance %s doesn't exist in the source environment %s", id, envid)
+		}
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			return fmt.Errorf("unable to read the source api instance %s: %s", id, string(b))
+		}
+		return fmt.Errorf("unable to read the source api instance %s: %s", id, err)
+	}
+	defer httpr.Body.Close()
+	return nil
+}
+
+func newApimPromotionBody(d *schema.ResourceData) (*apimPromotionBody, error) {
+	source, err := strconv.Atoi(d.Get("source_apim_id").(string))
+	if err != nil {
+		return nil, fmt.Errorf("invalid source_apim_id: %w", err)
+	}
+	body := &apimPromotionBody{
+		Promote: apimPromotion{
+			OriginApiId: source,
+			Policies:    apimPromotionEntity{AllEntities: d.Get("include_policies").(bool)},
+			Tiers:       apimPromotionEntity{AllEntities: d.Get("include_tiers").(bool)},
+			Alerts:      apimPromotionEntity{AllEntities: d.Get("include_alerts").(bool)},
+		},
+	}
+	if val, ok := d.GetOk("instance_label"); ok {
+		label := val.(string)
+		body.InstanceLabel = &label
+	}
+	return body, nil
+}
+
+func getApimPromotionAttributes() []string {
+	attributes := [...]string{
+		"instance_label", "technology", "asset_group_id", "asset_id", "asset_version",
+		"endpoint_uri", "status", "autodiscovery_instance_name",
+	}
+	return attributes[:]
+}
+
+func decomposeApimPromotionId(d *schema.ResourceData) (string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2]
+}
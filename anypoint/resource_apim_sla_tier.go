@@ -0,0 +1,319 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var APIM_SLA_TIER_STATUSES = []string{"ACTIVE", "DEPRECATED"}
+
+type apimSlaTier struct {
+	Id               int                `json:"id,omitempty"`
+	Name             string             `json:"name"`
+	Description      string             `json:"description"`
+	Limits           []apimSlaTierLimit `json:"limits"`
+	Status           string             `json:"status"`
+	AutoApprove      bool               `json:"autoApprove"`
+	ApplicationCount int                `json:"applicationCount,omitempty"`
+}
+
+type apimSlaTierLimit struct {
+	MaximumRequests          int  `json:"maximumRequests"`
+	TimePeriodInMilliseconds int  `json:"timePeriodInMilliseconds"`
+	Visible                  bool `json:"visible"`
+}
+
+func resourceApimSlaTier() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceApimSlaTierCreate,
+		ReadContext:   resourceApimSlaTierRead,
+		UpdateContext: resourceApimSlaTierUpdate,
+		DeleteContext: resourceApimSlaTierDelete,
+		Description: `
+		Creates an SLA tier for an API Manager instance.
+		Client applications request access to the api instance through a contract on one of its SLA tiers.
+		The limits of the tier are enforced by the ` + "`anypoint_apim_policy_rate_limiting_sla_based`" + ` policy.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{apim_id}/{tier_id}",
+			},
+			"tier_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SLA tier id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the api instance is defined.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where api instance is defined.",
+			},
+			"apim_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The api manager instance id where the SLA tier is defined.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the SLA tier.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The description of the SLA tier.",
+			},
+			"auto_approve": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the contracts requesting this tier are approved automatically.",
+			},
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "ACTIVE",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(APIM_SLA_TIER_STATUSES, false)),
+				Description: `
+				The status of the SLA tier. Deprecated tiers can't be requested by new contracts.
+				Supported values are ` + "`ACTIVE` and `DEPRECATED`" + `.
+				`,
+			},
+			"limits": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The limits of the SLA tier.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"maximum_requests": {
+							Type:             schema.TypeInt,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+							Description:      "The maximum number of requests allowed during the time period.",
+						},
+						"time_period_in_milliseconds": {
+							Type:             schema.TypeInt,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+							Description:      "The time period in milliseconds.",
+						},
+						"visible": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether the limit is visible to the consumers of the api in Exchange.",
+						},
+					},
+				},
+			},
+			"application_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The number of client applications having a contract on this tier.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "env_id", "apim_id", "tier_id"}),
+		},
+	}
+}
+
+func resourceApimSlaTierCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	apimid := d.Get("apim_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newApimSlaTierBody(d)
+	var res apimSlaTier
+	httpr, err := pco.restclient.Post(authctx, getApimSlaTiersPath(orgid, envid, apimid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create SLA tier for api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, strconv.Itoa(res.Id)}))
+	return resourceApimSlaTierRead(ctx, d, m)
+}
+
+func resourceApimSlaTierRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimSlaTierId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res apimSlaTier
+	httpr, err := pco.restclient.Get(authctx, getApimSlaTierPath(orgid, envid, apimid, id), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read SLA tier " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenApimSlaTier(&res)
+	for _, attr := range getApimSlaTierAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set SLA tier " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, apimid, id}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("apim_id", apimid)
+	d.Set("tier_id", id)
+	return diags
+}
+
+func resourceApimSlaTierUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimSlaTierId(d)
+	if d.HasChanges(getApimSlaTierUpdatableAttributes()...) {
+		authctx := getRestAuthCtx(ctx, &pco)
+		body := newApimSlaTierBody(d)
+		httpr, err := pco.restclient.Put(authctx, getApimSlaTierPath(orgid, envid, apimid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update SLA tier " + id + " of api " + apimid,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceApimSlaTierRead(ctx, d, m)
+}
+
+func resourceApimSlaTierDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, apimid, id := decomposeApimSlaTierId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getApimSlaTierPath(orgid, envid, apimid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete SLA tier " + id + " of api " + apimid,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newApimSlaTierBody(d *schema.ResourceData) *apimSlaTier {
+	tier := make(map[string]interface{})
+	for _, attr := range getApimSlaTierUpdatableAttributes() {
+		tier[attr] = d.Get(attr)
+	}
+	return newApimSlaTierBodyFromMap(tier)
+}
+
+func newApimSlaTierBodyFromMap(tier map[string]interface{}) *apimSlaTier {
+	limits := tier["limits"].([]interface{})
+	body := &apimSlaTier{
+		Name:        tier["name"].(string),
+		Description: tier["description"].(string),
+		Status:      tier["status"].(string),
+		AutoApprove: tier["auto_approve"].(bool),
+		Limits:      make([]apimSlaTierLimit, len(limits)),
+	}
+	for i, l := range limits {
+		limit := l.(map[string]interface{})
+		body.Limits[i] = apimSlaTierLimit{
+			MaximumRequests:          limit["maximum_requests"].(int),
+			TimePeriodInMilliseconds: limit["time_period_in_milliseconds"].(int),
+			Visible:                  limit["visible"].(bool),
+		}
+	}
+	return body
+}
+
+func flattenApimSlaTier(tier *apimSlaTier) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["name"] = tier.Name
+	result["description"] = tier.Description
+	result["status"] = tier.Status
+	result["auto_approve"] = tier.AutoApprove
+	result["application_count"] = tier.ApplicationCount
+	limits := make([]interface{}, len(tier.Limits))
+	for i, limit := range tier.Limits {
+		limits[i] = map[string]interface{}{
+			"maximum_requests":            limit.MaximumRequests,
+			"time_period_in_milliseconds": limit.TimePeriodInMilliseconds,
+			"visible":                     limit.Visible,
+		}
+	}
+	result["limits"] = limits
+	return result
+}
+
+func getApimSlaTierAttributes() []string {
+	return []string{"name", "description", "status", "auto_approve", "application_count", "limits"}
+}
+
+func getApimSlaTierUpdatableAttributes() []string {
+	return []string{"name", "description", "status", "auto_approve", "limits"}
+}
+
+func getApimSlaTiersPath(orgid, envid, apimid string) string {
+	return fmt.Sprintf(
+		"/apimanager/api/v1/organizations/%s/environments/%s/apis/%s/tiers",
+		url.PathEscape(orgid), url.PathEscape(envid), url.PathEscape(apimid),
+	)
+}
+
+func getApimSlaTierPath(orgid, envid, apimid, id string) string {
+	return getApimSlaTiersPath(orgid, envid, apimid) + "/" + url.PathEscape(id)
+}
+
+func decomposeApimSlaTierId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
@@ -0,0 +1,630 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim"
+	"github.com/mulesoft-anypoint/anypoint-client-go/apim_upstream"
+	application_manager_v2 "github.com/mulesoft-anypoint/anypoint-client-go/application_manager_v2"
+)
+
+// placeholder replaced by the name of the deployed mule app in the health check url and the upstream uri
+const APP_DEPLOYMENT_V2_STRATEGY_APP_NAME_PLACEHOLDER = "{app_name}"
+
+var DeplStrategyHealthCheckDefinition = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"url": {
+			Type:     schema.TypeString,
+			Required: true,
+			Description: `The url probed to assess the health of the newly deployed mule app.
+			The placeholder ` + "`{app_name}`" + ` is replaced by the name of the new mule app (i.e. ` + "`https://{app_name}.example.net/health`" + `).`,
+		},
+		"expected_status": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          200,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(100, 599)),
+			Description:      "The http status code expected from the health check url.",
+		},
+		"interval": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          10,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "The number of seconds between 2 probes.",
+		},
+		"timeout": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          600,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "The maximum number of seconds to wait for the new mule app to be running and healthy.",
+		},
+		"healthy_threshold": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          3,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "The number of consecutive successful probes required to consider the new mule app healthy.",
+		},
+		"unhealthy_threshold": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          3,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "The number of consecutive failed probes after which a canary step is aborted.",
+		},
+	},
+}
+
+var DeplStrategyDlbDefinition = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"org_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The business group id of the dedicated load balancer.",
+		},
+		"vpc_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The vpc id of the dedicated load balancer.",
+		},
+		"dlb_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The id of the dedicated load balancer. All the mappings targeting the active mule app are switched to the new one.",
+		},
+	},
+}
+
+var DeplStrategyApimDefinition = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"org_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The business group id of the api manager instance.",
+		},
+		"env_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The environment id of the api manager instance.",
+		},
+		"apim_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The id of the api manager instance.",
+		},
+		"upstream_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The id of the api manager instance upstream pointing to the active mule app.",
+		},
+		"upstream_uri": {
+			Type:     schema.TypeString,
+			Required: true,
+			Description: `The uri of the upstream. The placeholder ` + "`{app_name}`" + ` is replaced by the name of the new mule app
+			(i.e. ` + "`http://{app_name}.internal.example.net`" + `).`,
+		},
+	},
+}
+
+var DeplStrategyDefinition = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"type": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"blue_green", "canary"}, false)),
+			Description: `The deployment strategy, supported values are:
+			* ` + "`blue_green`" + `: the traffic is switched at once to the new mule app once healthy.
+			* ` + "`canary`" + `: the traffic is progressively shifted to the new mule app following ` + "`canary_weights`" + `. Requires the ` + "`apim`" + ` block.`,
+		},
+		"suffix": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "-green",
+			Description: "The suffix appended to the name of the mule app for the alternate deployment. The deployments alternate between the name and the suffixed name.",
+		},
+		"health_check": {
+			Type:        schema.TypeList,
+			MaxItems:    1,
+			Required:    true,
+			Description: "The health check of the new mule app. The new mule app is retired and the rollout aborted if it does not become healthy.",
+			Elem:        DeplStrategyHealthCheckDefinition,
+		},
+		"dlb": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: []string{"strategy.0.dlb", "strategy.0.apim"},
+			Description:  "The dedicated load balancer whose mappings are switched to the new mule app.",
+			Elem:         DeplStrategyDlbDefinition,
+		},
+		"apim": {
+			Type:         schema.TypeList,
+			MaxItems:     1,
+			Optional:     true,
+			ExactlyOneOf: []string{"strategy.0.dlb", "strategy.0.apim"},
+			Description:  "The api manager instance upstream switched to the new mule app.",
+			Elem:         DeplStrategyApimDefinition,
+		},
+		"canary_weights": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "The successive percentages of traffic routed to the new mule app before the full switch. Only used by the canary strategy.",
+			Elem: &schema.Schema{
+				Type:             schema.TypeInt,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntBetween(1, 99)),
+			},
+		},
+		"canary_interval": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          300,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			Description:      "The number of seconds each canary step is held while probing the new mule app.",
+		},
+		"retire_delay": {
+			Type:             schema.TypeInt,
+			Optional:         true,
+			Default:          0,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			Description:      "The number of seconds to wait after the switch before deleting the previous mule app, letting in-flight requests drain.",
+		},
+	},
+}
+
+// builds the deployment request body out of the resource data
+type appDeploymentV2BodyFunc func(d *schema.ResourceData) *application_manager_v2.DeploymentRequestBody
+
+// validates the strategy block
+func validateAppDeploymentV2Strategy(d *schema.ResourceDiff) error {
+	strategy_list := d.Get("strategy").([]interface{})
+	if len(strategy_list) == 0 || strategy_list[0] == nil {
+		return nil
+	}
+	strategy := strategy_list[0].(map[string]interface{})
+	if strategy["type"].(string) != "canary" {
+		return nil
+	}
+	if len(strategy["apim"].([]interface{})) == 0 {
+		return fmt.Errorf("the canary strategy requires the apim block")
+	}
+	weights := strategy["canary_weights"].([]interface{})
+	if len(weights) == 0 {
+		return fmt.Errorf("the canary strategy requires at least one weight in canary_weights")
+	}
+	for i := 1; i < len(weights); i++ {
+		if weights[i].(int) <= weights[i-1].(int) {
+			return fmt.Errorf("canary_weights should be strictly increasing")
+		}
+	}
+	return nil
+}
+
+/*
+Rolls out the changes of a deployment following the configured strategy:
+ 1. deploys a second mule app with the alternate name
+ 2. waits for it to be running and healthy
+ 3. switches the dlb mappings or the api manager upstream to it (progressively for canary)
+ 4. deletes the previous mule app
+
+The new mule app is deleted and the previous one left untouched if any step before the switch fails.
+*/
+func resourceAppDeploymentV2StrategyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}, newBody appDeploymentV2BodyFunc) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	name := d.Get("name").(string)
+	old_id := d.Id()
+	strategy := d.Get("strategy").([]interface{})[0].(map[string]interface{})
+	health_check := strategy["health_check"].([]interface{})[0].(map[string]interface{})
+	active_name := d.Get("active_name").(string)
+	if active_name == "" {
+		active_name = name
+	}
+	new_name := nextAppDeploymentV2StrategyName(name, strategy["suffix"].(string), active_name)
+	authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
+	//deploy the new mule app
+	body := newBody(d)
+	body.SetName(new_name)
+	// the new mule app is a brand-new deployment, it requires all the secure properties in clear
+	if err := setAppDeploymentV2BodySecureProps(d, body, false); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to deploy " + new_name + " for the " + strategy["type"].(string) + " rollout, invalid secure properties.",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	res, httpr, err := pco.appmanagerclient.DefaultApi.PostDeployment(authctx, orgid, envid).DeploymentRequestBody(*body).Execute()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to deploy " + new_name + " for the " + strategy["type"].(string) + " rollout.",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	new_id := res.GetId()
+	abort := func(summary string, cause error) diag.Diagnostics {
+		// keeps the previous state as the previous mule app is still the active one
+		d.Partial(true)
+		detail := cause.Error() + getAppDeploymentV2LogsTail(ctx, &pco, orgid, envid, new_id)
+		httpr, err := pco.appmanagerclient.DefaultApi.DeleteDeployment(authctx, orgid, envid, new_id).Execute()
+		if err != nil {
+			detail += "\nthe new mule app " + new_name + " could not be deleted: " + readRestClientErrorDetails(httpr, err)
+		} else {
+			defer httpr.Body.Close()
+		}
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Rollout of " + new_name + " aborted. " + summary,
+			Detail:   detail,
+		})
+	}
+	//wait for the new mule app to be healthy
+	if err := waitAppDeploymentV2Healthy(authctx, &pco, orgid, envid, new_id, new_name, health_check); err != nil {
+		return abort("The mule app did not become healthy.", err)
+	}
+	/*
+		once some traffic is cut over to the new mule app, the rollout is never aborted as it would delete the mule app serving the traffic,
+		the new mule app is kept as the active one and the failures are reported as warnings.
+	*/
+	cutover := false
+	partial := func(summary string, cause error) diag.Diagnostics {
+		d.SetId(new_id)
+		d.Set("active_name", new_name)
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Rollout of " + new_name + " partially completed. " + summary,
+			Detail:   cause.Error() + "\nthe previous mule app " + active_name + " (" + old_id + ") is kept deployed and should be deleted manually once the traffic is switched.",
+		})
+	}
+	//switch the traffic
+	if dlb_list := strategy["dlb"].([]interface{}); len(dlb_list) > 0 {
+		if err := switchAppDeploymentV2DlbMappings(ctx, &pco, dlb_list[0].(map[string]interface{}), active_name, new_name); err != nil {
+			return abort("Unable to switch the dedicated load balancer mappings.", err)
+		}
+		cutover = true
+	}
+	if apim_list := strategy["apim"].([]interface{}); len(apim_list) > 0 {
+		apim_d := apim_list[0].(map[string]interface{})
+		if strategy["type"].(string) == "canary" {
+			promoted, routed, err := runAppDeploymentV2Canary(ctx, &pco, strategy, apim_d, new_name)
+			if err != nil && promoted {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "The canary rollout of " + new_name + " completed but its cleanup failed.",
+					Detail:   err.Error(),
+				})
+			} else if err != nil && (cutover || routed) {
+				return partial("The canary rollout failed.", err)
+			} else if err != nil {
+				return abort("The canary rollout failed.", err)
+			}
+		} else if err := switchAppDeploymentV2ApimUpstream(ctx, &pco, apim_d, new_name); err != nil && cutover {
+			return partial("Unable to switch the api manager upstream.", err)
+		} else if err != nil {
+			return abort("Unable to switch the api manager upstream.", err)
+		}
+	}
+	d.SetId(new_id)
+	d.Set("active_name", new_name)
+	//retire the previous mule app
+	if err := sleepWithContext(ctx, time.Duration(strategy["retire_delay"].(int))*time.Second); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to retire the previous mule app " + active_name + ".",
+			Detail:   "the retire delay was interrupted, the previous mule app " + active_name + " (" + old_id + ") is still deployed and should be deleted manually: " + err.Error(),
+		})
+		return diags
+	}
+	httpr, err = pco.appmanagerclient.DefaultApi.DeleteDeployment(authctx, orgid, envid, old_id).Execute()
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to retire the previous mule app " + active_name + ".",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	return diags
+}
+
+// returns the name of the next mule app, alternating between the name and the suffixed name
+func nextAppDeploymentV2StrategyName(name, suffix, active_name string) string {
+	if active_name == name {
+		return name + suffix
+	}
+	return name
+}
+
+// keeps the configured name of the deployment and exposes the name of the deployed mule app as active_name
+func setAppDeploymentV2ActiveName(d *schema.ResourceData, data map[string]interface{}) {
+	val, ok := data["name"]
+	if !ok {
+		return
+	}
+	d.Set("active_name", val)
+	if d.Get("name").(string) != "" {
+		delete(data, "name")
+	}
+}
+
+// waits for the deployment to be applied and running, then for its health check to succeed
+func waitAppDeploymentV2Healthy(ctx context.Context, pco *ProviderConfOutput, orgid, envid, id, app_name string, health_check map[string]interface{}) error {
+	url := strings.ReplaceAll(health_check["url"].(string), APP_DEPLOYMENT_V2_STRATEGY_APP_NAME_PLACEHOLDER, app_name)
+	expected := health_check["expected_status"].(int)
+	interval := time.Duration(health_check["interval"].(int)) * time.Second
+	threshold := health_check["healthy_threshold"].(int)
+	deadline := time.Now().Add(time.Duration(health_check["timeout"].(int)) * time.Second)
+	successes := 0
+	var last error
+	for {
+		res, httpr, err := pco.appmanagerclient.DefaultApi.GetDeploymentById(ctx, orgid, envid, id).Execute()
+		if err != nil {
+			last = fmt.Errorf("unable to read deployment %s: %s", app_name, readRestClientErrorDetails(httpr, err))
+		} else {
+			httpr.Body.Close()
+			application := res.GetApplication()
+			if res.GetStatus() == "FAILED" {
+				return fmt.Errorf("deployment %s failed", app_name)
+			}
+			if res.GetStatus() == "APPLIED" && application.GetStatus() == "RUNNING" {
+				if err := probeAppDeploymentV2HealthCheck(ctx, url, expected); err != nil {
+					successes = 0
+					last = err
+				} else if successes++; successes >= threshold {
+					return nil
+				}
+			} else {
+				last = fmt.Errorf("deployment %s is %s and application is %s", app_name, res.GetStatus(), application.GetStatus())
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timeout while waiting for %s to be healthy, last error: %v", app_name, last)
+		}
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// probes the health check url for the given duration, fails after the configured number of consecutive failures
+func holdAppDeploymentV2Healthy(ctx context.Context, url string, health_check map[string]interface{}, duration time.Duration) error {
+	expected := health_check["expected_status"].(int)
+	interval := time.Duration(health_check["interval"].(int)) * time.Second
+	threshold := health_check["unhealthy_threshold"].(int)
+	deadline := time.Now().Add(duration)
+	failures := 0
+	for time.Now().Before(deadline) {
+		if err := probeAppDeploymentV2HealthCheck(ctx, url, expected); err != nil {
+			if failures++; failures >= threshold {
+				return err
+			}
+		} else {
+			failures = 0
+		}
+		if err := sleepWithContext(ctx, interval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func probeAppDeploymentV2HealthCheck(ctx context.Context, url string, expected int) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != expected {
+		return fmt.Errorf("health check %s returned status %d, expected %d", url, res.StatusCode, expected)
+	}
+	return nil
+}
+
+// replaces the app name of the dlb mappings targeting the active mule app
+func switchAppDeploymentV2DlbMappings(ctx context.Context, pco *ProviderConfOutput, dlb_d map[string]interface{}, from, to string) error {
+	orgid := dlb_d["org_id"].(string)
+	vpcid := dlb_d["vpc_id"].(string)
+	dlbid := dlb_d["dlb_id"].(string)
+	authctx := getDLBAuthCtx(ctx, pco)
+	res, httpr, err := pco.dlbclient.DefaultApi.OrganizationsOrgIdVpcsVpcIdLoadbalancersDlbIdGet(authctx, orgid, vpcid, dlbid).Execute()
+	if err != nil {
+		return fmt.Errorf("unable to read dlb %s: %s", dlbid, readRestClientErrorDetails(httpr, err))
+	}
+	httpr.Body.Close()
+	body := make([]map[string]interface{}, 0)
+	for i, endpoint := range res.GetSslEndpoints() {
+		for j, mapping := range endpoint.GetMappings() {
+			if mapping.GetAppName() == from {
+				body = append(body, map[string]interface{}{
+					"op":    "replace",
+					"path":  fmt.Sprintf("/sslEndpoints/%d/mappings/%d/appName", i, j),
+					"value": to,
+				})
+			}
+		}
+	}
+	if len(body) == 0 {
+		return fmt.Errorf("no mapping of dlb %s targets the mule app %s", dlbid, from)
+	}
+	_, httpr, err = pco.dlbclient.DefaultApi.OrganizationsOrgIdVpcsVpcIdLoadbalancersDlbIdPatch(authctx, orgid, vpcid, dlbid).RequestBody(body).Execute()
+	if err != nil {
+		return fmt.Errorf("unable to patch dlb %s: %s", dlbid, readRestClientErrorDetails(httpr, err))
+	}
+	httpr.Body.Close()
+	return nil
+}
+
+// points the api manager upstream to the given mule app
+func switchAppDeploymentV2ApimUpstream(ctx context.Context, pco *ProviderConfOutput, apim_d map[string]interface{}, app_name string) error {
+	orgid := apim_d["org_id"].(string)
+	envid := apim_d["env_id"].(string)
+	apimid := apim_d["apim_id"].(string)
+	upstreamid := apim_d["upstream_id"].(string)
+	uri := strings.ReplaceAll(apim_d["upstream_uri"].(string), APP_DEPLOYMENT_V2_STRATEGY_APP_NAME_PLACEHOLDER, app_name)
+	authctx := getApimUpstreamAuthCtx(ctx, pco)
+	body := apim_upstream.NewUpstreamPatchBody()
+	body.SetUri(uri)
+	_, httpr, err := pco.apimupstreamclient.DefaultApi.PatchApimInstanceUpstream(authctx, orgid, envid, apimid, upstreamid).UpstreamPatchBody(*body).Execute()
+	if err != nil {
+		return fmt.Errorf("unable to update upstream %s of api %s: %s", upstreamid, apimid, readRestClientErrorDetails(httpr, err))
+	}
+	httpr.Body.Close()
+	return nil
+}
+
+/*
+Shifts the traffic progressively to the new mule app.
+A temporary upstream pointing to the new mule app is added next to the active upstream in the api manager instance routing,
+its weight follows the canary weights while the new mule app is probed. Once all the steps succeeded,
+the active upstream is pointed to the new mule app and the original routing restored.
+The original routing is restored and the temporary upstream deleted on failure.
+Returns whether the active upstream targets the new mule app (promoted), even when the cleanup fails,
+and whether some traffic may still be routed to the new mule app (routed), i.e. when the routing couldn't be restored.
+*/
+func runAppDeploymentV2Canary(ctx context.Context, pco *ProviderConfOutput, strategy map[string]interface{}, apim_d map[string]interface{}, app_name string) (promoted bool, routed bool, err error) {
+	orgid := apim_d["org_id"].(string)
+	envid := apim_d["env_id"].(string)
+	apimid := apim_d["apim_id"].(string)
+	upstreamid := apim_d["upstream_id"].(string)
+	uri := strings.ReplaceAll(apim_d["upstream_uri"].(string), APP_DEPLOYMENT_V2_STRATEGY_APP_NAME_PLACEHOLDER, app_name)
+	health_check := strategy["health_check"].([]interface{})[0].(map[string]interface{})
+	health_url := strings.ReplaceAll(health_check["url"].(string), APP_DEPLOYMENT_V2_STRATEGY_APP_NAME_PLACEHOLDER, app_name)
+	interval := time.Duration(strategy["canary_interval"].(int)) * time.Second
+	apimctx := getApimAuthCtx(ctx, pco)
+	upstreamctx := getApimUpstreamAuthCtx(ctx, pco)
+	//read the current routing
+	details, httpr, err := pco.apimclient.DefaultApi.GetApimInstanceDetails(apimctx, orgid, envid, apimid).Execute()
+	if err != nil {
+		return false, false, fmt.Errorf("unable to read api %s: %s", apimid, readRestClientErrorDetails(httpr, err))
+	}
+	httpr.Body.Close()
+	routing := details.GetRouting()
+	if !isAppDeploymentV2UpstreamRouted(routing, upstreamid) {
+		return false, false, fmt.Errorf("the upstream %s is not used by any route of api %s", upstreamid, apimid)
+	}
+	original := newAppDeploymentV2CanaryRoutingBody(routing, upstreamid, "", 0)
+	//create the canary upstream
+	post_body := apim_upstream.NewUpstreamPostBody()
+	post_body.SetLabel(app_name)
+	post_body.SetUri(uri)
+	post_body.SetTlsContextNil()
+	upstream, httpr, err := pco.apimupstreamclient.DefaultApi.PostApimInstanceUpstream(upstreamctx, orgid, envid, apimid).UpstreamPostBody(*post_body).Execute()
+	if err != nil {
+		return false, false, fmt.Errorf("unable to create the canary upstream of api %s: %s", apimid, readRestClientErrorDetails(httpr, err))
+	}
+	httpr.Body.Close()
+	canaryid := upstream.GetId()
+	restore := func() error {
+		if _, httpr, err := pco.apimclient.DefaultApi.PatchApimInstance(apimctx, orgid, envid, apimid).Body(original).Execute(); err != nil {
+			return fmt.Errorf("unable to restore the routing of api %s: %s", apimid, readRestClientErrorDetails(httpr, err))
+		} else {
+			httpr.Body.Close()
+		}
+		if httpr, err := pco.apimupstreamclient.DefaultApi.DeleteApimInstanceUpstream(upstreamctx, orgid, envid, apimid, canaryid).Execute(); err != nil {
+			return fmt.Errorf("unable to delete the canary upstream %s: %s", canaryid, readRestClientErrorDetails(httpr, err))
+		} else {
+			httpr.Body.Close()
+		}
+		return nil
+	}
+	rollback := func(cause error) (bool, bool, error) {
+		if err := restore(); err != nil {
+			return false, true, fmt.Errorf("%v\n%v", cause, err)
+		}
+		return false, false, cause
+	}
+	for _, weight := range strategy["canary_weights"].([]interface{}) {
+		body := newAppDeploymentV2CanaryRoutingBody(routing, upstreamid, canaryid, weight.(int))
+		_, httpr, err := pco.apimclient.DefaultApi.PatchApimInstance(apimctx, orgid, envid, apimid).Body(body).Execute()
+		if err != nil {
+			return rollback(fmt.Errorf("unable to route %d%% of the traffic to %s: %s", weight.(int), app_name, readRestClientErrorDetails(httpr, err)))
+		}
+		httpr.Body.Close()
+		if err := holdAppDeploymentV2Healthy(ctx, health_url, health_check, interval); err != nil {
+			return rollback(fmt.Errorf("canary step at %d%% failed: %v", weight.(int), err))
+		}
+	}
+	//promote
+	if err := switchAppDeploymentV2ApimUpstream(ctx, pco, apim_d, app_name); err != nil {
+		return rollback(err)
+	}
+	if err := restore(); err != nil {
+		return true, true, fmt.Errorf("the upstream %s now targets %s but the canary cleanup failed: %v", upstreamid, app_name, err)
+	}
+	return true, true, nil
+}
+
+/*
+Returns the routing patch body where the routes including the given upstream share its weight with the canary upstream.
+The canary upstream is omitted when its id is empty.
+*/
+func newAppDeploymentV2CanaryRoutingBody(routing []apim.Routing, upstreamid, canaryid string, weight int) map[string]interface{} {
+	routes := make([]map[string]interface{}, len(routing))
+	for i, route := range routing {
+		item := make(map[string]interface{})
+		if val, ok := route.GetLabelOk(); ok {
+			item["label"] = *val
+		}
+		if val, ok := route.GetRulesOk(); ok {
+			item["rules"] = val
+		}
+		upstreams := make([]map[string]interface{}, 0)
+		for _, upstream := range route.GetUpstreams() {
+			w := int(upstream.GetWeight())
+			if upstream.GetId() == upstreamid && canaryid != "" {
+				canary_w := w * weight / 100
+				upstreams = append(upstreams, map[string]interface{}{"id": upstream.GetId(), "weight": w - canary_w})
+				upstreams = append(upstreams, map[string]interface{}{"id": canaryid, "weight": canary_w})
+				continue
+			}
+			upstreams = append(upstreams, map[string]interface{}{"id": upstream.GetId(), "weight": w})
+		}
+		item["upstreams"] = upstreams
+		routes[i] = item
+	}
+	return map[string]interface{}{"routing": routes}
+}
+
+func isAppDeploymentV2UpstreamRouted(routing []apim.Routing, upstreamid string) bool {
+	for _, route := range routing {
+		for _, upstream := range route.GetUpstreams() {
+			if upstream.GetId() == upstreamid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sleeps for the given duration unless the context is done first
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+		return nil
+	}
+}
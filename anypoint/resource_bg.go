@@ -977,6 +977,6 @@ func newEntitlementsFromD(d *schema.ResourceData) *org.EntitlementsCore {
  * Returns authentication context (includes authorization header)
  */
 func getBGAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, org.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, org.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, org.ContextServerIndex, pco.server_index)
 }
@@ -58,10 +58,12 @@ var DeplApplicationConfigPropsC2SSDefinition = &schema.Resource{
 			DefaultFunc: func() (interface{}, error) { return make(map[string]string), nil },
 		},
 		"secure_properties": {
-			Type:        schema.TypeMap,
-			Description: "The mule application secured properties.",
-			Optional:    true,
-			DefaultFunc: func() (interface{}, error) { return make(map[string]string), nil },
+			Type:             schema.TypeMap,
+			Description:      "The mule application secured properties. The values are stored in the state as salted hashes, the complete set of properties is sent on update (removed properties are deleted) and their clear values must therefore be configured.",
+			Sensitive:        true,
+			DiffSuppressFunc: diffSuppressAppDeploymentV2SecureProp,
+			Optional:         true,
+			DefaultFunc:      func() (interface{}, error) { return make(map[string]string), nil },
 		},
 	},
 }
@@ -520,6 +522,24 @@ func resourceCloudhub2SharedSpaceDeployment() *schema.Resource {
 				Description: "The last successfully deployed version",
 				Computed:    true,
 			},
+			"strategy": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Description: `The blue/green or canary strategy used to roll out changes of the application or the target.
+				Instead of updating the deployment in place, a second mule app is deployed with the alternate name, probed until healthy,
+				the traffic is switched to it through a dedicated load balancer or an api manager upstream and the previous mule app is deleted.
+				The rollout is aborted and the new mule app deleted if it does not become healthy.`,
+				Elem: DeplStrategyDefinition,
+			},
+			"active_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the deployed mule app. Alternates between the name and the suffixed name when a strategy is used.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			return validateAppDeploymentV2Strategy(rd)
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -535,6 +555,14 @@ func resourceCloudhub2SharedSpaceDeploymentCreate(ctx context.Context, d *schema
 	envid := d.Get("env_id").(string)
 	authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
 	body := newCloudhub2SharedSpaceDeploymentBody(d)
+	if err := setAppDeploymentV2BodySecureProps(d, body, false); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create deployment " + name + ", invalid secure properties.",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
 	//Execute post deployment
 	res, httpr, err := pco.appmanagerclient.DefaultApi.PostDeployment(authctx, orgid, envid).DeploymentRequestBody(*body).Execute()
 	if err != nil {
@@ -555,7 +583,7 @@ func resourceCloudhub2SharedSpaceDeploymentCreate(ctx context.Context, d *schema
 	}
 	defer httpr.Body.Close()
 	d.SetId(res.GetId())
-	return resourceCloudhub2SharedSpaceDeploymentRead(ctx, d, m)
+	return readAppDeploymentV2AfterChange(ctx, d, m, resourceCloudhub2SharedSpaceDeploymentRead)
 }
 
 func resourceCloudhub2SharedSpaceDeploymentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -590,6 +618,15 @@ func resourceCloudhub2SharedSpaceDeploymentRead(ctx context.Context, d *schema.R
 
 	//process data
 	data := flattenAppDeploymentV2(res)
+	if err := hashAppDeploymentV2SecureProps(d, data); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to hash App Deployment secure properties",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	setAppDeploymentV2ActiveName(d, data)
 	if err := setAppDeploymentV2AttributesToResourceData(d, data); err != nil {
 		diags := append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -611,6 +648,13 @@ func resourceCloudhub2SharedSpaceDeploymentUpdate(ctx context.Context, d *schema
 	if !d.HasChanges(getCloudhub2SharedSpaceDeploymentUpdatableAttributes()...) {
 		return diags
 	}
+	if _, ok := d.GetOk("strategy"); ok {
+		diags = append(diags, resourceAppDeploymentV2StrategyUpdate(ctx, d, m, newCloudhub2SharedSpaceDeploymentBody)...)
+		if diags.HasError() {
+			return diags
+		}
+		return append(diags, readAppDeploymentV2AfterChange(ctx, d, m, resourceCloudhub2SharedSpaceDeploymentRead)...)
+	}
 	pco := m.(ProviderConfOutput)
 	id := d.Id()
 	orgid := d.Get("org_id").(string)
@@ -618,6 +662,14 @@ func resourceCloudhub2SharedSpaceDeploymentUpdate(ctx context.Context, d *schema
 	name := d.Get("name").(string)
 	authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
 	body := newCloudhub2SharedSpaceDeploymentBody(d)
+	if err := setAppDeploymentV2BodySecureProps(d, body, true); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to update deployment " + name + ", invalid secure properties.",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
 	_, httpr, err := pco.appmanagerclient.DefaultApi.PatchDeployment(authctx, orgid, envid, id).DeploymentRequestBody(*body).Execute()
 	if err != nil {
 		var details string
@@ -636,7 +688,7 @@ func resourceCloudhub2SharedSpaceDeploymentUpdate(ctx context.Context, d *schema
 		return diags
 	}
 	defer httpr.Body.Close()
-	return resourceCloudhub2SharedSpaceDeploymentRead(ctx, d, m)
+	return readAppDeploymentV2AfterChange(ctx, d, m, resourceCloudhub2SharedSpaceDeploymentRead)
 }
 
 func resourceCloudhub2SharedSpaceDeploymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
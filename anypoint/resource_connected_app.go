@@ -3,10 +3,13 @@ package anypoint
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -23,6 +26,7 @@ func resourceConnectedApp() *schema.Resource {
 		DeleteContext: resourceConnectedAppDelete,
 		Description: `
 		Creates and manage a ` + "`" + `connected app` + "`" + `.
+		The client secret can be rotated without recreating the connected app using ` + "`rotate_secret_on`" + `.
 		`,
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -44,6 +48,7 @@ func resourceConnectedApp() *schema.Resource {
 			"secret": {
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				Sensitive:   true,
 				Description: "The secret of the connected app.",
 				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
@@ -173,6 +178,31 @@ func resourceConnectedApp() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"rotate_secret_on": {
+				Type:          schema.TypeMap,
+				Optional:      true,
+				ConflictsWith: []string{"secret"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: `
+				Arbitrary map of values that, when changed, regenerates the client secret of the connected app.
+				The new secret is saved in the ` + "`secret`" + ` attribute. Use a time-based value (ex: from the ` + "`time_rotating`" + ` resource) to rotate the secret periodically.
+				`,
+			},
+			"cert_expiry_warning_days": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     30,
+				Description: "The number of days before the expiry of the certificate of a jwt-bearer connected app with public keys from which a warning is raised.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			// the secret is regenerated by the rotation, its new value is only known after apply
+			if rd.Id() != "" && rd.HasChange("rotate_secret_on") {
+				return rd.SetNewComputed("secret")
+			}
+			return nil
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -290,7 +320,7 @@ func resourceConnectedAppRead(ctx context.Context, d *schema.ResourceData, m int
 	}
 	d.SetId(connappid)
 	d.Set("org_id", orgid)
-	return diags
+	return append(diags, checkConnectedAppCertExpiry(d)...)
 }
 
 func resourceConnectedAppUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -299,7 +329,14 @@ func resourceConnectedAppUpdate(ctx context.Context, d *schema.ResourceData, m i
 	orgid := d.Get("org_id").(string)
 	connappid := d.Id()
 	authctx := getConnectedAppAuthCtx(ctx, &pco)
-	if d.HasChanges(getConnectedAppAttributes()...) {
+	rotate := d.HasChange("rotate_secret_on")
+	attributes := getConnectedAppAttributes()
+	if rotate {
+		// the secret is computed by the rotation
+		attributes = FilterStrList(attributes, func(attr string) bool { return attr != "secret" })
+	}
+	// the connected app is patched before the rotation so that the patch doesn't restore the previous secret
+	if d.HasChanges(attributes...) {
 		body := newConnectedAppPatchBody(d)
 		//perform request
 		_, httpr, err := pco.connectedappclient.DefaultApi.UpdateConnectedApp(authctx, orgid, connappid).ConnectedAppPatchExt(*body).Execute()
@@ -335,6 +372,18 @@ func resourceConnectedAppUpdate(ctx context.Context, d *schema.ResourceData, m i
 				}
 			}
 		}
+	}
+	if rotate {
+		if err := rotateConnectedAppSecret(ctx, &pco, orgid, connappid); err != nil {
+			diags := append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to rotate connected-app " + connappid + " secret",
+				Detail:   err.Error(),
+			})
+			return diags
+		}
+	}
+	if rotate || d.HasChanges(attributes...) {
 		return resourceConnectedAppRead(ctx, d, m)
 	}
 	return diags
@@ -514,7 +563,8 @@ func newConnectedAppPatchBody(d *schema.ResourceData) *connected_app.ConnectedAp
 		body.SetClientUri(clienturi.(string))
 	}
 	// connected_app.ConnectedAppPatchExt extra attributes
-	if secret, ok := d.GetOk("secret"); ok {
+	// the secret is left as is when it is rotated
+	if secret, ok := d.GetOk("secret"); ok && !d.HasChange("rotate_secret_on") {
 		body.SetClientSecret(secret.(string))
 	}
 	if enabled, ok := d.GetOk("enabled"); ok {
@@ -523,6 +573,55 @@ func newConnectedAppPatchBody(d *schema.ResourceData) *connected_app.ConnectedAp
 	return body
 }
 
+// regenerates the client secret of the connected app, the new secret is loaded by the next read
+func rotateConnectedAppSecret(ctx context.Context, pco *ProviderConfOutput, orgid, connappid string) error {
+	authctx := getRestAuthCtx(ctx, pco)
+	path := fmt.Sprintf(
+		"/accounts/api/organizations/%s/connectedApplications/%s/secret/regenerate",
+		url.PathEscape(orgid), url.PathEscape(connappid),
+	)
+	httpr, err := pco.restclient.Post(authctx, path, nil, nil)
+	if err != nil {
+		return fmt.Errorf("%s", readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	return nil
+}
+
+// returns a warning when the certificate of a jwt-bearer connected app with public keys expires soon
+func checkConnectedAppCertExpiry(d *schema.ResourceData) diag.Diagnostics {
+	var diags diag.Diagnostics
+	granttypes := ListInterface2ListStrings(d.Get("grant_types").([]interface{}))
+	publickeys := d.Get("public_keys").([]interface{})
+	expiry := d.Get("cert_expiry").(string)
+	if !StringInSlice(granttypes, "urn:ietf:params:oauth:grant-type:jwt-bearer", true) || len(publickeys) == 0 || expiry == "" {
+		return diags
+	}
+	var date time.Time
+	var err error
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05.000Z", "2006-01-02"} {
+		if date, err = time.Parse(layout, expiry); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return diags
+	}
+	days := d.Get("cert_expiry_warning_days").(int)
+	if remaining := time.Until(date); remaining < time.Duration(days)*24*time.Hour {
+		summary := "The certificate of connected app " + d.Id() + " expires on " + expiry
+		if remaining <= 0 {
+			summary = "The certificate of connected app " + d.Id() + " expired on " + expiry
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  summary,
+			Detail:   "Rotate the public_keys of the connected app before the certificate expires, JWT authorization grants will be rejected afterwards.",
+		})
+	}
+	return diags
+}
+
 // Compares 2 scopes lists
 // returns true if they are the same, false otherwise
 func equalsConnectedAppScopes(old, new interface{}) bool {
@@ -618,7 +717,7 @@ func sortScopes(list []interface{}) {
  * Returns authentication context (includes authorization header)
  */
 func getConnectedAppAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, connected_app.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, connected_app.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, connected_app.ContextServerIndex, pco.server_index)
 }
 
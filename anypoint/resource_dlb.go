@@ -731,7 +731,7 @@ func compareDLBStates(old, new string) bool {
 
 // Returns authentication context (includes authorization header)
 func getDLBAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, dlb.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, dlb.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, dlb.ContextServerIndex, pco.server_index)
 }
 
@@ -233,7 +233,7 @@ func newENVPutBody(d *schema.ResourceData) *env.EnvCore {
  * Returns authentication context (includes authorization header)
  */
 func getENVAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, env.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, env.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, env.ContextServerIndex, pco.server_index)
 }
 
@@ -0,0 +1,273 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var EXCHANGE_CLIENT_APP_GRANT_TYPES = []string{
+	"authorization_code",
+	"implicit",
+	"password",
+	"client_credentials",
+	"refresh_token",
+	"urn:ietf:params:oauth:grant-type:jwt-bearer",
+	"urn:ietf:params:oauth:grant-type:saml2-bearer",
+}
+
+type exchangeClientApp struct {
+	Id           int      `json:"id,omitempty"`
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Url          string   `json:"url"`
+	RedirectUri  []string `json:"redirectUri"`
+	GrantTypes   []string `json:"grantTypes"`
+	ClientId     string   `json:"clientId,omitempty"`
+	ClientSecret string   `json:"clientSecret,omitempty"`
+}
+
+func resourceExchangeClientApplication() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceExchangeClientApplicationCreate,
+		ReadContext:   resourceExchangeClientApplicationRead,
+		UpdateContext: resourceExchangeClientApplicationUpdate,
+		DeleteContext: resourceExchangeClientApplicationDelete,
+		Description: `
+		Creates a client application in Exchange.
+		The client application requests access to api instances through contracts (see ` + "`anypoint_apim_contract`" + `).
+		Its client id and secret are the credentials validated by the ` + "`anypoint_apim_policy_client_id_enforcement`" + ` policy.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{application_id}",
+			},
+			"application_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The client application id.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the client application is defined.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the client application.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The description of the client application.",
+			},
+			"url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The url of the client application.",
+			},
+			"redirect_uris": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The OAuth redirect uris of the client application.",
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.IsURLWithScheme([]string{"http", "https"})),
+				},
+			},
+			"grant_types": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Description: `
+				The OAuth grant types of the client application.
+				Supported values are ` + "`authorization_code`, `implicit`, `password`, `client_credentials`, `refresh_token`, `urn:ietf:params:oauth:grant-type:jwt-bearer` and `urn:ietf:params:oauth:grant-type:saml2-bearer`" + `.
+				`,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(EXCHANGE_CLIENT_APP_GRANT_TYPES, false)),
+				},
+			},
+			"client_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The client id of the client application.",
+			},
+			"client_secret": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The client secret of the client application.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "application_id"}),
+		},
+	}
+}
+
+func resourceExchangeClientApplicationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newExchangeClientAppBody(d)
+	var res exchangeClientApp
+	httpr, err := pco.restclient.Post(authctx, getExchangeClientAppsPath(orgid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create client application " + body.Name,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, strconv.Itoa(res.Id)}))
+	// the secret is only returned on creation, the read keeps the one in the state when it is missing
+	d.Set("client_secret", res.ClientSecret)
+	return resourceExchangeClientApplicationRead(ctx, d, m)
+}
+
+func resourceExchangeClientApplicationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeExchangeClientAppId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res exchangeClientApp
+	httpr, err := pco.restclient.Get(authctx, getExchangeClientAppPath(orgid, id), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read client application " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenExchangeClientApp(&res)
+	for _, attr := range getExchangeClientAppAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set client application " + id + " details attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	// the secret is only returned to the owners of the application, keep the known one otherwise
+	if val := data["client_secret"].(string); val != "" {
+		d.Set("client_secret", val)
+	}
+	d.SetId(ComposeResourceId([]string{orgid, id}))
+	d.Set("org_id", orgid)
+	d.Set("application_id", id)
+	return diags
+}
+
+func resourceExchangeClientApplicationUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeExchangeClientAppId(d)
+	if d.HasChanges(getExchangeClientAppUpdatableAttributes()...) {
+		authctx := getRestAuthCtx(ctx, &pco)
+		body := newExchangeClientAppBody(d)
+		httpr, err := pco.restclient.Put(authctx, getExchangeClientAppPath(orgid, id), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update client application " + id,
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceExchangeClientApplicationRead(ctx, d, m)
+}
+
+func resourceExchangeClientApplicationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, id := decomposeExchangeClientAppId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getExchangeClientAppPath(orgid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete client application " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newExchangeClientAppBody(d *schema.ResourceData) *exchangeClientApp {
+	return &exchangeClientApp{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Url:         d.Get("url").(string),
+		RedirectUri: ListInterface2ListStrings(d.Get("redirect_uris").([]interface{})),
+		GrantTypes:  ListInterface2ListStrings(d.Get("grant_types").([]interface{})),
+	}
+}
+
+func flattenExchangeClientApp(app *exchangeClientApp) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["name"] = app.Name
+	result["description"] = app.Description
+	result["url"] = app.Url
+	result["redirect_uris"] = app.RedirectUri
+	result["grant_types"] = app.GrantTypes
+	result["client_id"] = app.ClientId
+	result["client_secret"] = app.ClientSecret
+	return result
+}
+
+func getExchangeClientAppAttributes() []string {
+	return []string{"name", "description", "url", "redirect_uris", "grant_types", "client_id"}
+}
+
+func getExchangeClientAppUpdatableAttributes() []string {
+	return []string{"name", "description", "url", "redirect_uris", "grant_types"}
+}
+
+func getExchangeClientAppsPath(orgid string) string {
+	return fmt.Sprintf("/apiplatform/repository/v2/organizations/%s/applications", url.PathEscape(orgid))
+}
+
+func getExchangeClientAppPath(orgid, id string) string {
+	return getExchangeClientAppsPath(orgid) + "/" + url.PathEscape(id)
+}
+
+func decomposeExchangeClientAppId(d *schema.ResourceData) (string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1]
+}
@@ -2,7 +2,11 @@ package anypoint
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -10,6 +14,8 @@ import (
 	rtf "github.com/mulesoft-anypoint/anypoint-client-go/rtf"
 )
 
+const FABRICS_POLL_INTERVAL = 30 * time.Second
+
 func resourceFabrics() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFabricsCreate,
@@ -18,6 +24,8 @@ func resourceFabrics() *schema.Resource {
 		DeleteContext: resourceFabricsDelete,
 		Description: `
 		Creates a ` + "`" + `Runtime Fabrics` + "`" + ` instance.
+		The ` + "`helm_values`" + ` attribute provides a ready-to-use values.yaml to install the runtime fabrics agent helm chart,
+		and the resource optionally waits until the fabrics is activated and healthy.
 		`,
 		Schema: map[string]*schema.Schema{
 			"id": {
@@ -114,6 +122,38 @@ func resourceFabrics() *schema.Resource {
 				Computed:    true,
 				Description: "The activation data to use during installation of fabrics on the kubernetes cluster. Only available when instance is created and not activated yet.",
 			},
+			"mule_license": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The base64 encoded mule license key, included in the generated helm values.",
+			},
+			"helm_values": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+				Description: `
+				The values.yaml content to use to install the ` + "`rtf-agent`" + ` helm chart on the kubernetes cluster.
+				It includes the activation data, the image registry of the organization and the vendor specific settings.
+				Only available when instance is created and not activated yet.
+				`,
+			},
+			"wait_for_active": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `
+				Whether to wait until the fabrics is ` + "`Active`" + ` and all of its health probes are healthy.
+				The agent needs to be installed on the cluster outside of this resource while waiting.
+				When set on an existing fabrics, the wait is performed during the update.
+				`,
+			},
+			"wait_timeout": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     1800,
+				Description: "The maximum time to wait for the fabrics to be active, in seconds.",
+			},
 			"seconds_since_heartbeat": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -202,7 +242,14 @@ func resourceFabricsCreate(ctx context.Context, d *schema.ResourceData, m interf
 
 	id := res.GetId()
 	d.SetId(id)
-	return resourceFabricsRead(ctx, d, m)
+	diags = append(diags, resourceFabricsRead(ctx, d, m)...)
+	if diags.HasError() {
+		return diags
+	}
+	if d.Get("wait_for_active").(bool) {
+		diags = append(diags, waitFabricsActive(ctx, d, m)...)
+	}
+	return diags
 }
 
 func resourceFabricsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -244,12 +291,26 @@ func resourceFabricsRead(ctx context.Context, d *schema.ResourceData, m interfac
 		})
 		return diags
 	}
+	if helm_values, err := newFabricsHelmValues(ctx, &pco, orgid, res, d.Get("mule_license").(string)); err == nil {
+		d.Set("helm_values", helm_values)
+	} else {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Unable to generate helm values of fabrics " + fabricsid,
+			Detail:   err.Error(),
+		})
+	}
 	d.SetId(fabricsid)
 	d.Set("org_id", orgid)
 	return diags
 }
 
 func resourceFabricsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("wait_for_active") && d.Get("wait_for_active").(bool) {
+		if diags := waitFabricsActive(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
 	return resourceFabricsRead(ctx, d, m)
 }
 
@@ -285,6 +346,123 @@ func resourceFabricsDelete(ctx context.Context, d *schema.ResourceData, m interf
 	return diags
 }
 
+// waits until the fabrics is active and healthy, then refreshes the resource data
+func waitFabricsActive(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	fabricsid := d.Id()
+	if isComposedResourceId(fabricsid) {
+		orgid, fabricsid = decomposeFabricsId(d)
+	}
+	timeout := time.Duration(d.Get("wait_timeout").(int)) * time.Second
+	deadline := time.Now().Add(timeout)
+	authctx := getFabricsAuthCtx(ctx, &pco)
+	var last error
+	for {
+		res, httpr, err := pco.rtfclient.DefaultApi.GetFabrics(authctx, orgid, fabricsid).Execute()
+		if err != nil {
+			last = fmt.Errorf("unable to read fabrics %s: %s", fabricsid, readRestClientErrorDetails(httpr, err))
+		} else {
+			httpr.Body.Close()
+			if status := res.GetStatus(); status != "Active" {
+				last = fmt.Errorf("the fabrics status is %s", status)
+			} else if failures, err := getFabricsFailedProbes(ctx, &pco, orgid, fabricsid); err != nil {
+				last = err
+			} else if len(failures) > 0 {
+				last = fmt.Errorf("the fabrics is not healthy:\n\t- %s", strings.Join(failures, "\n\t- "))
+			} else {
+				return resourceFabricsRead(ctx, d, m)
+			}
+		}
+		if time.Now().After(deadline) {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Timeout while waiting for fabrics " + fabricsid + " to be active",
+				Detail:   fmt.Sprintf("last error: %v", last),
+			})
+			return diags
+		}
+		if err := sleepWithContext(ctx, FABRICS_POLL_INTERVAL); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+			return diags
+		}
+	}
+}
+
+// returns the failed health probes of the fabrics formatted as "{component}: {probe} {reason}".
+// only the given health components are checked, all of them when none is given.
+func getFabricsFailedProbes(ctx context.Context, pco *ProviderConfOutput, orgid, fabricsid string, components ...string) ([]string, error) {
+	authctx := getFabricsAuthCtx(ctx, pco)
+	res, httpr, err := pco.rtfclient.DefaultApi.GetFabricsHealth(authctx, orgid, fabricsid).Execute()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fabrics %s health: %s", fabricsid, readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	failures := make([]string, 0)
+	for component, val := range flattenFabricsHealthData(res) {
+		if len(components) > 0 && !StringInSlice(components, component, false) {
+			continue
+		}
+		list, ok := val.([]interface{})
+		if !ok || len(list) == 0 {
+			continue
+		}
+		status := list[0].(map[string]interface{})
+		if healthy, ok := status["healthy"].(bool); !ok || healthy {
+			continue
+		}
+		probes, _ := status["failed_probes"].([]interface{})
+		if len(probes) == 0 {
+			failures = append(failures, component+": unhealthy")
+		}
+		for _, p := range probes {
+			probe := p.(map[string]interface{})
+			failures = append(failures, fmt.Sprintf("%s: %v %v", component, probe["name"], probe["reason"]))
+		}
+	}
+	sort.Strings(failures)
+	return failures, nil
+}
+
+// generates the values.yaml of the rtf-agent helm chart, only while the fabrics is not activated yet
+func newFabricsHelmValues(ctx context.Context, pco *ProviderConfOutput, orgid string, fabrics *rtf.Fabrics, license string) (string, error) {
+	activation := fabrics.GetActivationData()
+	if activation == "" {
+		return "", nil
+	}
+	authctx := getFabricsAuthCtx(ctx, pco)
+	props, httpr, err := pco.rtfclient.DefaultApi.GetFabricsHelmRepoProps(authctx, orgid).Execute()
+	if err != nil {
+		return "", fmt.Errorf("unable to read fabrics helm repository props: %s", readRestClientErrorDetails(httpr, err))
+	}
+	defer httpr.Body.Close()
+	var b strings.Builder
+	b.WriteString("activationData: " + activation + "\n")
+	if license != "" {
+		b.WriteString("muleLicense: " + license + "\n")
+	}
+	b.WriteString("proxy:\n")
+	b.WriteString("  http_proxy: \"\"\n")
+	b.WriteString("  http_no_proxy: \"\"\n")
+	b.WriteString("monitoringProxy: \"\"\n")
+	b.WriteString("global:\n")
+	b.WriteString("  image:\n")
+	b.WriteString("    rtfRegistry: " + props.GetRTF_IMAGE_REGISTRY_ENDPOINT() + "\n")
+	b.WriteString("    pullSecretName: rtf-pull-secret\n")
+	b.WriteString("  containerLogPaths:\n")
+	b.WriteString("    - /var/lib/docker/containers\n")
+	b.WriteString("    - /var/log/containers\n")
+	b.WriteString("    - /var/log/pods\n")
+	if fabrics.GetVendor() == "openshift" {
+		b.WriteString("  openshift: true\n")
+	}
+	b.WriteString("  crds:\n")
+	b.WriteString("    install: true\n")
+	b.WriteString("  authorizedNamespaces: false\n")
+	return b.String(), nil
+}
+
 func prepareFabricsPostBody(d *schema.ResourceData) *rtf.FabricsPostBody {
 	body := rtf.NewFabricsPostBody()
 	body.SetName(d.Get("name").(string))
@@ -302,6 +480,6 @@ func decomposeFabricsId(d *schema.ResourceData) (string, string) {
  * Returns authentication context (includes authorization header)
  */
 func getFabricsAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, rtf.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, rtf.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, rtf.ContextServerIndex, pco.server_index)
 }
@@ -0,0 +1,236 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	rtf "github.com/mulesoft-anypoint/anypoint-client-go/rtf"
+)
+
+// the associations of a fabrics are replaced as a whole by the platform,
+// the changes of single associations are serialized to avoid losing concurrent updates within the same run.
+var fabricsAssociationMutex sync.Mutex
+
+// the number of attempts to apply a change to the associations when it is lost to a concurrent update
+const FABRICS_ASSOCIATION_MAX_ATTEMPTS = 3
+
+func resourceFabricsAssociation() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFabricsAssociationCreate,
+		ReadContext:   resourceFabricsAssociationRead,
+		DeleteContext: resourceFabricsAssociationDelete,
+		Description: `
+		Manages a single ` + "`" + `Runtime Fabrics` + "`" + ` association with an environment of an organization.
+		The other associations of the fabrics are kept as they are, which allows several teams to manage their own associations on a shared fabrics.
+		NOTE: Don't use this resource together with ` + "`anypoint_fabrics_associations`" + ` for the same fabrics.
+		NOTE: The platform replaces the associations of a fabrics as a whole. The changes are serialized within a terraform run,
+		and each change is verified and retried when it is lost, but concurrent terraform runs changing the associations
+		of the same fabrics are unsafe: a run may overwrite the associations changed by another one.
+		`,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{fabrics_id}/{associated_org_id}/{associated_env_id}",
+			},
+			"association_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of the association in the platform.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the fabrics is hosted.",
+			},
+			"fabrics_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The unique id of the fabrics instance in the platform.",
+			},
+			"associated_org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id to associate with fabrics.",
+			},
+			"associated_env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment to associate with fabrics.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "fabrics_id", "associated_org_id", "associated_env_id"}),
+		},
+	}
+}
+
+func resourceFabricsAssociationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	fabricsid := d.Get("fabrics_id").(string)
+	assocorgid := d.Get("associated_org_id").(string)
+	assocenvid := d.Get("associated_env_id").(string)
+	if err := updateFabricsAssociation(ctx, &pco, orgid, fabricsid, assocorgid, assocenvid, true); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create fabrics " + fabricsid + " association with env " + assocenvid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid, assocorgid, assocenvid}))
+	return resourceFabricsAssociationRead(ctx, d, m)
+}
+
+func resourceFabricsAssociationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid, assocorgid, assocenvid := decomposeFabricsAssociationId(d)
+	associations, err := getFabricsAssociationsList(ctx, &pco, orgid, fabricsid)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read fabrics " + fabricsid + " associations",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	association := findFabricsAssociation(associations, assocorgid, assocenvid)
+	if association == nil {
+		log.Printf("[WARN] fabrics %s association with env %s not found, removing it from the state", fabricsid, assocenvid)
+		d.SetId("")
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid, assocorgid, assocenvid}))
+	d.Set("association_id", association["id"])
+	d.Set("org_id", orgid)
+	d.Set("fabrics_id", fabricsid)
+	d.Set("associated_org_id", assocorgid)
+	d.Set("associated_env_id", assocenvid)
+	return diags
+}
+
+func resourceFabricsAssociationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid, assocorgid, assocenvid := decomposeFabricsAssociationId(d)
+	if err := updateFabricsAssociation(ctx, &pco, orgid, fabricsid, assocorgid, assocenvid, false); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to delete fabrics " + fabricsid + " association with env " + assocenvid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+/*
+Adds (present is true) or removes the given association while keeping the other associations of the fabrics.
+The associations are replaced as a whole by the platform, they are read back after the change
+and the change is applied again when it was lost to a concurrent update.
+*/
+func updateFabricsAssociation(ctx context.Context, pco *ProviderConfOutput, orgid, fabricsid, assocorgid, assocenvid string, present bool) error {
+	fabricsAssociationMutex.Lock()
+	defer fabricsAssociationMutex.Unlock()
+	for attempt := 1; ; attempt++ {
+		associations, err := getFabricsAssociationsList(ctx, pco, orgid, fabricsid)
+		if err != nil {
+			return err
+		}
+		if (findFabricsAssociation(associations, assocorgid, assocenvid) != nil) == present {
+			return nil
+		}
+		if attempt > FABRICS_ASSOCIATION_MAX_ATTEMPTS {
+			return fmt.Errorf("the change was overwritten by a concurrent update of the fabrics associations %d times", FABRICS_ASSOCIATION_MAX_ATTEMPTS)
+		}
+		if attempt > 1 {
+			log.Printf("[WARN] fabrics %s association with env %s lost to a concurrent update, retrying", fabricsid, assocenvid)
+			if err := sleepWithContext(ctx, time.Duration(attempt)*time.Second); err != nil {
+				return err
+			}
+		}
+		if present {
+			associations = append(associations, map[string]interface{}{"org_id": assocorgid, "env_id": assocenvid})
+		} else {
+			associations = FilterMapList(associations, func(m map[string]interface{}) bool {
+				return m["org_id"] != assocorgid || m["env_id"] != assocenvid
+			})
+		}
+		if err := postFabricsAssociationsList(ctx, pco, orgid, fabricsid, associations); err != nil {
+			return err
+		}
+	}
+}
+
+// returns the current associations of the fabrics as a list of flattened associations
+func getFabricsAssociationsList(ctx context.Context, pco *ProviderConfOutput, orgid, fabricsid string) ([]interface{}, error) {
+	authctx := getFabricsAuthCtx(ctx, pco)
+	res, httpr, err := pco.rtfclient.DefaultApi.GetFabricsAssociations(authctx, orgid, fabricsid).Execute()
+	if err != nil {
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			return nil, fmt.Errorf("%s", string(b))
+		}
+		return nil, err
+	}
+	defer httpr.Body.Close()
+	return flattenFabricsAssociationsData(res), nil
+}
+
+// replaces the associations of the fabrics with the given list of flattened associations
+func postFabricsAssociationsList(ctx context.Context, pco *ProviderConfOutput, orgid, fabricsid string, associations []interface{}) error {
+	authctx := getFabricsAuthCtx(ctx, pco)
+	body := rtf.NewFabricsAssociationsPostBody()
+	inners := make([]rtf.FabricsAssociationsPostBodyAssociationsInner, len(associations))
+	for i, association := range associations {
+		parsedAssoc := association.(map[string]interface{})
+		inner := rtf.NewFabricsAssociationsPostBodyAssociationsInner()
+		inner.SetOrganizationId(parsedAssoc["org_id"].(string))
+		inner.SetEnvironment(parsedAssoc["env_id"].(string))
+		inners[i] = *inner
+	}
+	body.SetAssociations(inners)
+	_, httpr, err := pco.rtfclient.DefaultApi.PostFabricsAssociations(authctx, orgid, fabricsid).FabricsAssociationsPostBody(*body).Execute()
+	if err != nil {
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			return fmt.Errorf("%s", string(b))
+		}
+		return err
+	}
+	defer httpr.Body.Close()
+	return nil
+}
+
+func findFabricsAssociation(associations []interface{}, assocorgid, assocenvid string) map[string]interface{} {
+	for _, a := range associations {
+		association := a.(map[string]interface{})
+		if association["org_id"] == assocorgid && association["env_id"] == assocenvid {
+			return association
+		}
+	}
+	return nil
+}
+
+func decomposeFabricsAssociationId(d *schema.ResourceData) (string, string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2], s[3]
+}
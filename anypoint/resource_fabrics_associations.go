@@ -6,17 +6,24 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	rtf "github.com/mulesoft-anypoint/anypoint-client-go/rtf"
 )
 
+var FABRICS_ASSOCIATIONS_ON_DESTROY = []string{"reset_to_all_sandboxes", "clear", "retain"}
+
 func resourceFabricsAssociations() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceFabricsAssociationsCreate,
 		ReadContext:   resourceFabricsAssociationsRead,
+		UpdateContext: resourceFabricsAssociationsUpdate,
 		DeleteContext: resourceFabricsAssociationsDelete,
 		Description: `
 		Manages ` + "`" + `Runtime Fabrics` + "`" + ` Environment associations.
-		NOTE: The fabrics will be associated with all sandbox environments in every available org when this resource is deleted.
+		This resource manages all the associations of the fabrics, use ` + "`anypoint_fabrics_association`" + ` to manage a single association
+		when several teams share the same fabrics.
+		NOTE: By default, the fabrics will be associated with all sandbox environments in every available org when this resource is deleted.
+		Use ` + "`on_destroy`" + ` to change this behavior.
 		`,
 		Schema: map[string]*schema.Schema{
 			"last_updated": {
@@ -42,6 +49,20 @@ func resourceFabricsAssociations() *schema.Resource {
 				ForceNew:    true,
 				Description: "The unique id of the fabrics instance in the platform.",
 			},
+			"on_destroy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "reset_to_all_sandboxes",
+				ValidateDiagFunc: validation.ToDiagFunc(
+					validation.StringInSlice(FABRICS_ASSOCIATIONS_ON_DESTROY, false),
+				),
+				Description: `
+				The behavior when this resource is deleted. The following values are supported:
+					* reset_to_all_sandboxes: associates the fabrics with all sandbox environments in every available org.
+					* clear: removes all the associations of the fabrics.
+					* retain: keeps the associations of the fabrics as they are.
+				`,
+			},
 			"associations": {
 				Type:        schema.TypeSet,
 				Required:    true,
@@ -157,11 +178,25 @@ func resourceFabricsAssociationsRead(ctx context.Context, d *schema.ResourceData
 	return diags
 }
 
+// only on_destroy is updatable, it is used when the resource is deleted
+func resourceFabricsAssociationsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return resourceFabricsAssociationsRead(ctx, d, m)
+}
+
 func resourceFabricsAssociationsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	var diags diag.Diagnostics
 	pco := m.(ProviderConfOutput)
 	fabricsid := d.Get("fabrics_id").(string)
 	orgid := d.Get("org_id").(string)
+	if isComposedResourceId(d.Id()) {
+		orgid, fabricsid = decomposeFabricsAssociationsId(d)
+	}
+	if d.Get("on_destroy").(string) == "retain" {
+		// d.SetId("") is automatically called assuming delete returns no errors, but
+		// it is added here for explicitness.
+		d.SetId("")
+		return diags
+	}
 	authctx := getFabricsAuthCtx(ctx, &pco)
 	body := prepareFabricsAssociationsDeleteBody(d)
 	//perform request
@@ -211,8 +246,12 @@ func prepareFabricsAssociationsPostBody(d *schema.ResourceData) *rtf.FabricsAsso
 	return body
 }
 
-func prepareFabricsAssociationsDeleteBody(_ *schema.ResourceData) *rtf.FabricsAssociationsPostBody {
+func prepareFabricsAssociationsDeleteBody(d *schema.ResourceData) *rtf.FabricsAssociationsPostBody {
 	body := rtf.NewFabricsAssociationsPostBody()
+	if d.Get("on_destroy").(string) == "clear" {
+		body.SetAssociations([]rtf.FabricsAssociationsPostBodyAssociationsInner{})
+		return body
+	}
 	env := "sandbox"
 	org := "all"
 	associations := []rtf.FabricsAssociationsPostBodyAssociationsInner{
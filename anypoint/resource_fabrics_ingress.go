@@ -0,0 +1,242 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type fabricsIngress struct {
+	Domains          []string `json:"domains"`
+	IngressClassName string   `json:"ingressClassName,omitempty"`
+}
+
+func resourceFabricsIngress() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFabricsIngressCreate,
+		ReadContext:   resourceFabricsIngressRead,
+		UpdateContext: resourceFabricsIngressUpdate,
+		DeleteContext: resourceFabricsIngressDelete,
+		Description: `
+		Manages the ingress configuration of a ` + "`" + `Runtime Fabrics` + "`" + ` instance.
+		The domains are used to build the public urls of the applications deployed on the fabrics,
+		the ` + "`inbound_public_url`" + ` of ` + "`anypoint_rtf_deployment`" + ` is validated against them during the plan.
+		The domains of the fabrics are cleared when this resource is deleted.
+		NOTE: The public url templates aren't exposed by the Runtime Fabrics API yet, they are defined by the ingress template of the cluster.
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{fabrics_id}",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the fabrics is hosted.",
+			},
+			"fabrics_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The unique id of the fabrics instance in the platform.",
+			},
+			"domains": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The list of domains used by the applications deployed on the fabrics, ex: *.example.com",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"ingress_class_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "The kubernetes ingress class used by the ingress resources of the applications.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "fabrics_id"}),
+		},
+	}
+}
+
+func resourceFabricsIngressCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	fabricsid := d.Get("fabrics_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newFabricsIngressBody(d)
+	httpr, err := pco.restclient.Put(authctx, getFabricsIngressPath(orgid, fabricsid), body, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to configure fabrics " + fabricsid + " ingress",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid}))
+	return resourceFabricsIngressRead(ctx, d, m)
+}
+
+func resourceFabricsIngressRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsIngressId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res fabricsIngress
+	httpr, err := pco.restclient.Get(authctx, getFabricsIngressPath(orgid, fabricsid), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read fabrics " + fabricsid + " ingress",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := map[string]interface{}{
+		"domains":            res.Domains,
+		"ingress_class_name": res.IngressClassName,
+	}
+	for _, attr := range getFabricsIngressAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set fabrics " + fabricsid + " ingress attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid}))
+	d.Set("org_id", orgid)
+	d.Set("fabrics_id", fabricsid)
+	return diags
+}
+
+func resourceFabricsIngressUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsIngressId(d)
+	if d.HasChanges(getFabricsIngressAttributes()...) {
+		authctx := getRestAuthCtx(ctx, &pco)
+		body := newFabricsIngressBody(d)
+		httpr, err := pco.restclient.Put(authctx, getFabricsIngressPath(orgid, fabricsid), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update fabrics " + fabricsid + " ingress",
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceFabricsIngressRead(ctx, d, m)
+}
+
+func resourceFabricsIngressDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsIngressId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	body := &fabricsIngress{Domains: []string{}}
+	httpr, err := pco.restclient.Put(authctx, getFabricsIngressPath(orgid, fabricsid), body, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to clear fabrics " + fabricsid + " ingress",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newFabricsIngressBody(d *schema.ResourceData) *fabricsIngress {
+	return &fabricsIngress{
+		Domains:          ListInterface2ListStrings(d.Get("domains").([]interface{})),
+		IngressClassName: d.Get("ingress_class_name").(string),
+	}
+}
+
+/*
+Returns an error if the host of one of the given comma separated urls doesn't match any of the domains.
+A domain starting with "*." matches any sub-domain.
+*/
+func validateFabricsPublicUrls(urls string, domains []string) error {
+	if len(domains) == 0 {
+		return nil
+	}
+	for _, raw := range strings.Split(urls, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			return fmt.Errorf("invalid public url %s", raw)
+		}
+		if !isFabricsDomainMatching(u.Hostname(), domains) {
+			return fmt.Errorf("the host of public url %s doesn't match any of the fabrics domains: %s", raw, strings.Join(domains, ", "))
+		}
+	}
+	return nil
+}
+
+func isFabricsDomainMatching(host string, domains []string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range domains {
+		domain = strings.ToLower(domain)
+		if strings.HasPrefix(domain, "*.") {
+			if strings.HasSuffix(host, domain[1:]) && len(host) > len(domain)-1 {
+				return true
+			}
+		} else if host == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func getFabricsIngressAttributes() []string {
+	return []string{"domains", "ingress_class_name"}
+}
+
+func getFabricsIngressPath(orgid, fabricsid string) string {
+	return fmt.Sprintf(
+		"/runtimefabric/api/organizations/%s/fabrics/%s/ingress",
+		url.PathEscape(orgid), url.PathEscape(fabricsid),
+	)
+}
+
+func decomposeFabricsIngressId(d *schema.ResourceData) (string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1]
+}
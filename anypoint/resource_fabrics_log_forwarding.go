@@ -0,0 +1,278 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var FABRICS_LOG_FORWARDING_TYPES = []string{"elasticsearch", "splunk", "syslog", "azure-log-analytics", "graylog", "http"}
+
+type fabricsLogForwarding struct {
+	AnypointMonitoring     bool                         `json:"anypointMonitoring"`
+	AppScopedLogForwarding bool                         `json:"appScopedLogForwarding"`
+	Outputs                []fabricsLogForwardingOutput `json:"outputs"`
+}
+
+type fabricsLogForwardingOutput struct {
+	Type   string            `json:"type"`
+	Host   string            `json:"host"`
+	Port   int               `json:"port"`
+	Config map[string]string `json:"config,omitempty"`
+}
+
+func resourceFabricsLogForwarding() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFabricsLogForwardingCreate,
+		ReadContext:   resourceFabricsLogForwardingRead,
+		UpdateContext: resourceFabricsLogForwardingUpdate,
+		DeleteContext: resourceFabricsLogForwardingDelete,
+		Description: `
+		Manages the log forwarding configuration of a ` + "`" + `Runtime Fabrics` + "`" + ` instance.
+		The external log forwarding is disabled when this resource is deleted.
+		NOTE: The Mule license isn't exposed by the Runtime Fabrics API yet, only its expiry date is, it is applied on the cluster (i.e. using rtfctl).
+		`,
+		Schema: map[string]*schema.Schema{
+			"last_updated": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The last time this resource has been updated locally.",
+			},
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{fabrics_id}",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the fabrics is hosted.",
+			},
+			"fabrics_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The unique id of the fabrics instance in the platform.",
+			},
+			"anypoint_monitoring": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the application logs are forwarded to anypoint monitoring.",
+			},
+			"app_scoped_log_forwarding": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the log forwarding can be configured per application.",
+			},
+			"outputs": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The external log forwarding outputs.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateDiagFunc: validation.ToDiagFunc(
+								validation.StringInSlice(FABRICS_LOG_FORWARDING_TYPES, false),
+							),
+							Description: "The type of output. Supported values are `elasticsearch`, `splunk`, `syslog`, `azure-log-analytics`, `graylog` and `http`.",
+						},
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The host of the output.",
+						},
+						"port": {
+							Type:             schema.TypeInt,
+							Required:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.IsPortNumber),
+							Description:      "The port of the output.",
+						},
+						"config": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "The output specific settings, ex: index, token, user, password, tls.",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importComposedResourceIdPassthrough([]string{"org_id", "fabrics_id"}),
+		},
+	}
+}
+
+func resourceFabricsLogForwardingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	fabricsid := d.Get("fabrics_id").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	body := newFabricsLogForwardingBody(d)
+	httpr, err := pco.restclient.Put(authctx, getFabricsLogForwardingPath(orgid, fabricsid), body, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to configure fabrics " + fabricsid + " log forwarding",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid}))
+	return resourceFabricsLogForwardingRead(ctx, d, m)
+}
+
+func resourceFabricsLogForwardingRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsLogForwardingId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	//perform request
+	var res fabricsLogForwarding
+	httpr, err := pco.restclient.Get(authctx, getFabricsLogForwardingPath(orgid, fabricsid), nil, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to read fabrics " + fabricsid + " log forwarding",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//process data
+	data := flattenFabricsLogForwarding(&res)
+	for _, attr := range getFabricsLogForwardingAttributes() {
+		if err := d.Set(attr, data[attr]); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to set fabrics " + fabricsid + " log forwarding attributes",
+				Detail:   fmt.Sprintf("unable to set attribute %s: %s", attr, err),
+			})
+			return diags
+		}
+	}
+	d.SetId(ComposeResourceId([]string{orgid, fabricsid}))
+	d.Set("org_id", orgid)
+	d.Set("fabrics_id", fabricsid)
+	return diags
+}
+
+func resourceFabricsLogForwardingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsLogForwardingId(d)
+	if d.HasChanges(getFabricsLogForwardingAttributes()...) {
+		authctx := getRestAuthCtx(ctx, &pco)
+		body := newFabricsLogForwardingBody(d)
+		httpr, err := pco.restclient.Put(authctx, getFabricsLogForwardingPath(orgid, fabricsid), body, nil)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  "Unable to update fabrics " + fabricsid + " log forwarding",
+				Detail:   readRestClientErrorDetails(httpr, err),
+			})
+			return diags
+		}
+		defer httpr.Body.Close()
+		d.Set("last_updated", time.Now().Format(time.RFC850))
+	}
+	return resourceFabricsLogForwardingRead(ctx, d, m)
+}
+
+func resourceFabricsLogForwardingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, fabricsid := decomposeFabricsLogForwardingId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	body := &fabricsLogForwarding{
+		AnypointMonitoring: true,
+		Outputs:            []fabricsLogForwardingOutput{},
+	}
+	httpr, err := pco.restclient.Put(authctx, getFabricsLogForwardingPath(orgid, fabricsid), body, nil)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to disable fabrics " + fabricsid + " log forwarding",
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+func newFabricsLogForwardingBody(d *schema.ResourceData) *fabricsLogForwarding {
+	outputs := d.Get("outputs").([]interface{})
+	body := &fabricsLogForwarding{
+		AnypointMonitoring:     d.Get("anypoint_monitoring").(bool),
+		AppScopedLogForwarding: d.Get("app_scoped_log_forwarding").(bool),
+		Outputs:                make([]fabricsLogForwardingOutput, len(outputs)),
+	}
+	for i, o := range outputs {
+		output := o.(map[string]interface{})
+		config := make(map[string]string)
+		for k, v := range output["config"].(map[string]interface{}) {
+			config[k] = v.(string)
+		}
+		body.Outputs[i] = fabricsLogForwardingOutput{
+			Type:   output["type"].(string),
+			Host:   output["host"].(string),
+			Port:   output["port"].(int),
+			Config: config,
+		}
+	}
+	return body
+}
+
+func flattenFabricsLogForwarding(lf *fabricsLogForwarding) map[string]interface{} {
+	result := make(map[string]interface{})
+	result["anypoint_monitoring"] = lf.AnypointMonitoring
+	result["app_scoped_log_forwarding"] = lf.AppScopedLogForwarding
+	outputs := make([]interface{}, len(lf.Outputs))
+	for i, output := range lf.Outputs {
+		outputs[i] = map[string]interface{}{
+			"type":   output.Type,
+			"host":   output.Host,
+			"port":   output.Port,
+			"config": output.Config,
+		}
+	}
+	result["outputs"] = outputs
+	return result
+}
+
+func getFabricsLogForwardingAttributes() []string {
+	return []string{"anypoint_monitoring", "app_scoped_log_forwarding", "outputs"}
+}
+
+func getFabricsLogForwardingPath(orgid, fabricsid string) string {
+	return fmt.Sprintf(
+		"/runtimefabric/api/organizations/%s/fabrics/%s/logForwarding",
+		url.PathEscape(orgid), url.PathEscape(fabricsid),
+	)
+}
+
+func decomposeFabricsLogForwardingId(d *schema.ResourceData) (string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1]
+}
@@ -0,0 +1,313 @@
+package anypoint
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+type flexGatewayRegistrationBody struct {
+	Name string `json:"name"`
+}
+
+type flexGatewayRegistration struct {
+	Id                    string `json:"id"`
+	Name                  string `json:"name"`
+	OrganizationId        string `json:"organizationId"`
+	EnvironmentId         string `json:"environmentId"`
+	Certificate           string `json:"certificate"`
+	PrivateKey            string `json:"privateKey"`
+	PlatformCACertificate string `json:"platformCACertificate"`
+}
+
+func resourceFlexGatewayRegistration() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFlexGatewayRegistrationCreate,
+		ReadContext:   resourceFlexGatewayRegistrationRead,
+		DeleteContext: resourceFlexGatewayRegistrationDelete,
+		Description: `
+		Registers a flex gateway target in connected mode, as performed by ` + "`flexctl register`" + `.
+		The generated ` + "`registration.yaml`" + ` content and certificate material are exposed as sensitive attributes
+		to be mounted in the flex gateway replicas. The target is deregistered when this resource is destroyed.
+		NOTE: This resource can't be imported as the certificate material is only returned by the registration.
+		`,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The unique id of this resource composed of {org_id}/{env_id}/{target_id}",
+			},
+			"target_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The id of the registered flex gateway target.",
+			},
+			"org_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The organization id where the flex gateway target is registered.",
+			},
+			"env_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The environment id where the flex gateway target is registered.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the flex gateway target.",
+			},
+			"registration_token": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				ForceNew:  true,
+				Sensitive: true,
+				Description: `
+				The registration token used to perform the registration, see ` + "`anypoint_flexgateway_registration_token`" + `.
+				The provider's credentials are used by default.
+				`,
+			},
+			"registration_yaml": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The content of the registration.yaml file used to run the flex gateway in connected mode.",
+			},
+			"certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM certificate of the flex gateway target.",
+			},
+			"private_key": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM private key of the flex gateway target's certificate.",
+			},
+			"platform_ca_certificate": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The PEM certificate of the anypoint platform's certificate authority.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the flex gateway target",
+			},
+			"replicas": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of replicas by status type",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"status": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The status of the flex gateway replicas",
+						},
+						"count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of the flex gateway replicas",
+						},
+						"certificate_expiration_dates": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "Certificate expiration dates for the given replicas",
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+			"tags": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of tags",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"last_update": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Last update date-time",
+			},
+			"versions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of version numbers",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the version number",
+			},
+		},
+	}
+}
+
+func resourceFlexGatewayRegistrationCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid := d.Get("org_id").(string)
+	envid := d.Get("env_id").(string)
+	name := d.Get("name").(string)
+	authctx := getRestAuthCtx(ctx, &pco)
+	if val, ok := d.GetOk("registration_token"); ok {
+		authctx = context.WithValue(authctx, RestContextAccessToken, val.(string))
+	}
+	//perform request
+	body := &flexGatewayRegistrationBody{Name: name}
+	var res flexGatewayRegistration
+	httpr, err := pco.restclient.Post(authctx, getFlexGatewayRegistrationsPath(orgid, envid), body, &res)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to register flex gateway " + name,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//the certificate material is only returned by the registration
+	d.SetId(ComposeResourceId([]string{orgid, envid, res.Id}))
+	d.Set("target_id", res.Id)
+	d.Set("certificate", res.Certificate)
+	d.Set("private_key", res.PrivateKey)
+	d.Set("platform_ca_certificate", res.PlatformCACertificate)
+	d.Set("registration_yaml", newFlexGatewayRegistrationYaml(&res, orgid, envid, pco.server_index))
+	return resourceFlexGatewayRegistrationRead(ctx, d, m)
+}
+
+func resourceFlexGatewayRegistrationRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, id := decomposeFlexGatewayRegistrationId(d)
+	authctx := getFlexGatewayAuthCtx(ctx, &pco)
+	//perform request
+	res, httpr, err := pco.flexgatewayclient.DefaultApi.GetFlexGatewayTargetById(authctx, orgid, envid, id).Execute()
+	if err != nil {
+		// the target has been deregistered outside of terraform, the registration will be performed again
+		if httpr != nil && httpr.StatusCode == http.StatusNotFound {
+			log.Printf("[WARN] flex gateway target %s not found in env %s, removing it from the state", id, envid)
+			d.SetId("")
+			return diags
+		}
+		var details string
+		if httpr != nil && httpr.StatusCode >= 400 {
+			defer httpr.Body.Close()
+			b, _ := io.ReadAll(httpr.Body)
+			details = string(b)
+		} else {
+			details = err.Error()
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to get flex gateway target " + id,
+			Detail:   details,
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	//parse data
+	data := flattenFlexGatewayTargetDetails(res)
+	if err := setFlexGatewayTargetAttributesToResourceData(d, data); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to set flex gateway target attributes",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	d.SetId(ComposeResourceId([]string{orgid, envid, id}))
+	d.Set("org_id", orgid)
+	d.Set("env_id", envid)
+	d.Set("target_id", id)
+	return diags
+}
+
+func resourceFlexGatewayRegistrationDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	pco := m.(ProviderConfOutput)
+	orgid, envid, id := decomposeFlexGatewayRegistrationId(d)
+	authctx := getRestAuthCtx(ctx, &pco)
+	httpr, err := pco.restclient.Delete(authctx, getFlexGatewayRegistrationPath(orgid, envid, id))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to deregister flex gateway target " + id,
+			Detail:   readRestClientErrorDetails(httpr, err),
+		})
+		return diags
+	}
+	defer httpr.Body.Close()
+	// d.SetId("") is automatically called assuming delete returns no errors, but
+	// it is added here for explicitness.
+	d.SetId("")
+	return diags
+}
+
+// generates the registration.yaml content as written by flexctl
+func newFlexGatewayRegistrationYaml(reg *flexGatewayRegistration, orgid, envid string, server_index int) string {
+	platformurl := REST_CLIENT_SERVERS[0]
+	if server_index >= 0 && server_index < len(REST_CLIENT_SERVERS) {
+		platformurl = REST_CLIENT_SERVERS[server_index]
+	}
+	var b strings.Builder
+	b.WriteString("apiVersion: gateway.mulesoft.com/v1alpha1\n")
+	b.WriteString("kind: Configuration\n")
+	b.WriteString("metadata:\n")
+	b.WriteString("  name: registration\n")
+	b.WriteString("spec:\n")
+	b.WriteString("  platformConnection:\n")
+	b.WriteString("    agentId: " + reg.Id + "\n")
+	b.WriteString("    environmentId: " + envid + "\n")
+	b.WriteString("    organizationId: " + orgid + "\n")
+	b.WriteString("    platformUrl: " + platformurl + "/apigateway/ccs\n")
+	b.WriteString("    platformCertificate: |\n" + indentFlexGatewayRegistrationPem(reg.Certificate))
+	b.WriteString("    platformCertificateKey: |\n" + indentFlexGatewayRegistrationPem(reg.PrivateKey))
+	b.WriteString("    platformCACert: |\n" + indentFlexGatewayRegistrationPem(reg.PlatformCACertificate))
+	return b.String()
+}
+
+// indents each line of the given PEM block to be used as a yaml literal block
+func indentFlexGatewayRegistrationPem(pem string) string {
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(pem), "\n") {
+		b.WriteString("      " + line + "\n")
+	}
+	return b.String()
+}
+
+func getFlexGatewayRegistrationsPath(orgid, envid string) string {
+	return fmt.Sprintf(
+		"/standalone/api/v1/organizations/%s/environments/%s/gateways",
+		url.PathEscape(orgid), url.PathEscape(envid),
+	)
+}
+
+func getFlexGatewayRegistrationPath(orgid, envid, id string) string {
+	return getFlexGatewayRegistrationsPath(orgid, envid) + "/" + url.PathEscape(id)
+}
+
+func decomposeFlexGatewayRegistrationId(d *schema.ResourceData) (string, string, string) {
+	s := DecomposeResourceId(d.Id())
+	return s[0], s[1], s[2]
+}
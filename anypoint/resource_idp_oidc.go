@@ -428,7 +428,7 @@ func newOIDCPatchBody(d *schema.ResourceData) (*idp.IdpPatchBody, diag.Diagnosti
 }
 
 func getIDPAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, idp.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, idp.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, idp.ContextServerIndex, pco.server_index)
 }
 
@@ -332,7 +332,7 @@ func getRolegroupWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getRoleGroupAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, rolegroup.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, rolegroup.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, rolegroup.ContextServerIndex, pco.server_index)
 }
 
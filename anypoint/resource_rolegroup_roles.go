@@ -345,7 +345,7 @@ func getAssignedRolesAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getRoleAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, role.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, role.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, role.ContextServerIndex, pco.server_index)
 }
 
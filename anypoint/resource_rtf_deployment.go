@@ -2,8 +2,11 @@ package anypoint
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"log"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -57,10 +60,12 @@ var DeplApplicationConfigPropsRTFDefinition = &schema.Resource{
 			DefaultFunc: func() (interface{}, error) { return make(map[string]string), nil },
 		},
 		"secure_properties": {
-			Type:        schema.TypeMap,
-			Description: "The mule application secured properties.",
-			Optional:    true,
-			DefaultFunc: func() (interface{}, error) { return make(map[string]string), nil },
+			Type:             schema.TypeMap,
+			Description:      "The mule application secured properties. The values are stored in the state as salted hashes, the complete set of properties is sent on update (removed properties are deleted) and their clear values must therefore be configured.",
+			Sensitive:        true,
+			DiffSuppressFunc: diffSuppressAppDeploymentV2SecureProp,
+			Optional:         true,
+			DefaultFunc:      func() (interface{}, error) { return make(map[string]string), nil },
 		},
 	},
 }
@@ -177,6 +182,7 @@ var DeplTargetDeplSettHttpRTFDefinition = &schema.Resource{
 			Description: `The ingress url(s).
 			If you need to use multiple ingress urls, separete them with commas.
 			example: http://example.mulesoft.terraform.net/(.+)
+			The urls are validated during the plan against the domains of the target fabrics, see ` + "`anypoint_fabrics_ingress`" + `.
 			`,
 			Optional: true,
 			Default:  "",
@@ -492,6 +498,9 @@ var DeplTargetRTFDefinition = &schema.Resource{
 	},
 }
 
+// the fabrics health checks required to deploy an application
+var RTF_DEPLOYMENT_HEALTH_COMPONENTS = []string{"manage_deployments", "load_balancing", "infrastructure"}
+
 func resourceRTFDeployment() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceRTFDeploymentCreate,
@@ -525,6 +534,16 @@ func resourceRTFDeployment() *schema.Resource {
 				ForceNew:    true,
 				Description: "The name of the deployed mule app.",
 			},
+			"require_healthy_target": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				Description: `
+				Whether to check the health of the target fabrics before deploying.
+				The deployment fails fast with the reasons of the failed probes when one of the
+				` + "`" + strings.Join(RTF_DEPLOYMENT_HEALTH_COMPONENTS, "`, `") + "`" + ` health checks of the fabrics is not healthy.
+				`,
+			},
 			"creation_date": {
 				Type:        schema.TypeInt,
 				Computed:    true,
@@ -570,6 +589,27 @@ func resourceRTFDeployment() *schema.Resource {
 				Description: "The last successfully deployed version",
 				Computed:    true,
 			},
+			"strategy": {
+				Type:     schema.TypeList,
+				MaxItems: 1,
+				Optional: true,
+				Description: `The blue/green or canary strategy used to roll out changes of the application or the target.
+				Instead of updating the deployment in place, a second mule app is deployed with the alternate name, probed until healthy,
+				the traffic is switched to it through a dedicated load balancer or an api manager upstream and the previous mule app is deleted.
+				The rollout is aborted and the new mule app deleted if it does not become healthy.`,
+				Elem: DeplStrategyDefinition,
+			},
+			"active_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the deployed mule app. Alternates between the name and the suffixed name when a strategy is used.",
+			},
+		},
+		CustomizeDiff: func(ctx context.Context, rd *schema.ResourceDiff, i interface{}) error {
+			if err := validateRTFDeploymentPublicUrl(ctx, rd, i); err != nil {
+				return err
+			}
+			return validateAppDeploymentV2Strategy(rd)
 		},
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
@@ -583,8 +623,19 @@ func resourceRTFDeploymentCreate(ctx context.Context, d *schema.ResourceData, m
 	name := d.Get("name").(string)
 	orgid := d.Get("org_id").(string)
 	envid := d.Get("env_id").(string)
+	if diags := checkRTFDeploymentTargetHealth(ctx, d, &pco); diags.HasError() {
+		return diags
+	}
 	authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
 	body := newRTFDeploymentBody(d)
+	if err := setAppDeploymentV2BodySecureProps(d, body, false); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create deployment " + name + ", invalid secure properties.",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
 	//Execute post deployment
 	res, httpr, err := pco.appmanagerclient.DefaultApi.PostDeployment(authctx, orgid, envid).DeploymentRequestBody(*body).Execute()
 	if err != nil {
@@ -605,7 +656,7 @@ func resourceRTFDeploymentCreate(ctx context.Context, d *schema.ResourceData, m
 	}
 	defer httpr.Body.Close()
 	d.SetId(res.GetId())
-	return resourceRTFDeploymentRead(ctx, d, m)
+	return readAppDeploymentV2AfterChange(ctx, d, m, resourceRTFDeploymentRead)
 }
 
 func resourceRTFDeploymentRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -640,6 +691,15 @@ func resourceRTFDeploymentRead(ctx context.Context, d *schema.ResourceData, m in
 
 	//process data
 	data := flattenAppDeploymentV2(res)
+	if err := hashAppDeploymentV2SecureProps(d, data); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to hash App Deployment secure properties",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	setAppDeploymentV2ActiveName(d, data)
 	if err := setAppDeploymentV2AttributesToResourceData(d, data); err != nil {
 		diags := append(diags, diag.Diagnostic{
 			Severity: diag.Error,
@@ -662,12 +722,30 @@ func resourceRTFDeploymentUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diags
 	}
 	pco := m.(ProviderConfOutput)
+	if diags := checkRTFDeploymentTargetHealth(ctx, d, &pco); diags.HasError() {
+		return diags
+	}
+	if _, ok := d.GetOk("strategy"); ok {
+		diags = append(diags, resourceAppDeploymentV2StrategyUpdate(ctx, d, m, newRTFDeploymentBody)...)
+		if diags.HasError() {
+			return diags
+		}
+		return append(diags, readAppDeploymentV2AfterChange(ctx, d, m, resourceRTFDeploymentRead)...)
+	}
 	id := d.Id()
 	orgid := d.Get("org_id").(string)
 	envid := d.Get("env_id").(string)
 	name := d.Get("name").(string)
 	authctx := getAppDeploymentV2AuthCtx(ctx, &pco)
 	body := newRTFDeploymentBody(d)
+	if err := setAppDeploymentV2BodySecureProps(d, body, true); err != nil {
+		diags := append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to update deployment " + name + ", invalid secure properties.",
+			Detail:   err.Error(),
+		})
+		return diags
+	}
 	_, httpr, err := pco.appmanagerclient.DefaultApi.PatchDeployment(authctx, orgid, envid, id).DeploymentRequestBody(*body).Execute()
 	if err != nil {
 		var details string
@@ -686,7 +764,7 @@ func resourceRTFDeploymentUpdate(ctx context.Context, d *schema.ResourceData, m
 		return diags
 	}
 	defer httpr.Body.Close()
-	return resourceRTFDeploymentRead(ctx, d, m)
+	return readAppDeploymentV2AfterChange(ctx, d, m, resourceRTFDeploymentRead)
 }
 
 func resourceRTFDeploymentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -930,3 +1008,85 @@ func getRTFDeploymentUpdatableAttributes() []string {
 	attributes := [...]string{"application", "target"}
 	return attributes[:]
 }
+
+// fails when the target fabrics is not healthy and the deployment requires a healthy target
+func checkRTFDeploymentTargetHealth(ctx context.Context, d *schema.ResourceData, pco *ProviderConfOutput) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !d.Get("require_healthy_target").(bool) {
+		return diags
+	}
+	orgid := d.Get("org_id").(string)
+	target_list_d := d.Get("target").([]interface{})
+	fabricsid := target_list_d[0].(map[string]interface{})["target_id"].(string)
+	failures, err := getFabricsFailedProbes(ctx, pco, orgid, fabricsid, RTF_DEPLOYMENT_HEALTH_COMPONENTS...)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to check the health of target fabrics " + fabricsid,
+			Detail:   err.Error(),
+		})
+		return diags
+	}
+	if len(failures) > 0 {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "The target fabrics " + fabricsid + " is not healthy",
+			Detail:   "failed probes:\n\t- " + strings.Join(failures, "\n\t- "),
+		})
+	}
+	return diags
+}
+
+/*
+Validates the inbound public urls of the deployment against the domains configured on the target fabrics.
+The validation is skipped when the values are not known yet or when the fabrics can't be fetched.
+*/
+func validateRTFDeploymentPublicUrl(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("target") {
+		return nil
+	}
+	attributes := []string{
+		"org_id", "target", "target.0.target_id",
+		"target.0.deployment_settings.0.http.0.inbound_public_url",
+	}
+	for _, attr := range attributes {
+		if !d.NewValueKnown(attr) {
+			return nil
+		}
+	}
+	pco, ok := m.(ProviderConfOutput)
+	if !ok {
+		return nil
+	}
+	target_list_d := d.Get("target").([]interface{})
+	if len(target_list_d) == 0 || target_list_d[0] == nil {
+		return nil
+	}
+	target_d := target_list_d[0].(map[string]interface{})
+	deployment_settings_list_d := target_d["deployment_settings"].([]interface{})
+	if len(deployment_settings_list_d) == 0 || deployment_settings_list_d[0] == nil {
+		return nil
+	}
+	http_list_d, ok := deployment_settings_list_d[0].(map[string]interface{})["http"].([]interface{})
+	if !ok || len(http_list_d) == 0 || http_list_d[0] == nil {
+		return nil
+	}
+	urls := http_list_d[0].(map[string]interface{})["inbound_public_url"].(string)
+	if urls == "" {
+		return nil
+	}
+	orgid := d.Get("org_id").(string)
+	fabricsid := target_d["target_id"].(string)
+	authctx := getFabricsAuthCtx(ctx, &pco)
+	res, httpr, err := pco.rtfclient.DefaultApi.GetFabrics(authctx, orgid, fabricsid).Execute()
+	if err != nil {
+		log.Printf("[WARN] Unable to get fabrics %s, skipping inbound_public_url validation: %s\n", fabricsid, readRestClientErrorDetails(httpr, err))
+		return nil
+	}
+	defer httpr.Body.Close()
+	ingress := res.GetIngress()
+	if err := validateFabricsPublicUrls(urls, ingress.GetDomains()); err != nil {
+		return fmt.Errorf("invalid inbound_public_url for target %s: %s", fabricsid, err.Error())
+	}
+	return nil
+}
@@ -269,6 +269,6 @@ func decomposeSecretGroupId(d *schema.ResourceData) (string, string, string) {
  * Returns authentication context (includes authorization header)
  */
 func getSecretGroupAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, secretgroup.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, secretgroup.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, secretgroup.ContextServerIndex, pco.server_index)
 }
@@ -316,6 +316,6 @@ func getSgCertificateUpdatableAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getSgCertificateAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, secretgroup_certificate.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, secretgroup_certificate.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, secretgroup_certificate.ContextServerIndex, pco.server_index)
 }
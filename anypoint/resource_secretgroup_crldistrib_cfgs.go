@@ -270,7 +270,7 @@ func getSgCrlDistribCfgsUpdatableAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getSgCrlDistribCfgsAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, secretgroup_crl_distributor_configs.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, secretgroup_crl_distributor_configs.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, secretgroup_crl_distributor_configs.ContextServerIndex, pco.server_index)
 }
 
@@ -539,6 +539,6 @@ func getSgKeystoreOthersUpdatableAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getSgKeystoreAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, secretgroup_keystore.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, secretgroup_keystore.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, secretgroup_keystore.ContextServerIndex, pco.server_index)
 }
@@ -429,6 +429,6 @@ func getSgTruststoreOthersUpdatableAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getSgTruststoreAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, secretgroup_truststore.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, secretgroup_truststore.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, secretgroup_truststore.ContextServerIndex, pco.server_index)
 }
@@ -318,7 +318,7 @@ func getTeamPutWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getTeamAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, team.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, team.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, team.ContextServerIndex, pco.server_index)
 }
 
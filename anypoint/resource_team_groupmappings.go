@@ -300,7 +300,7 @@ func equalTeamGroupMapping(old, new interface{}) bool {
  * Returns authentication context (includes authorization header)
  */
 func getTeamGroupMappingsAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, team_group_mappings.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, team_group_mappings.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, team_group_mappings.ContextServerIndex, pco.server_index)
 }
 
@@ -260,7 +260,7 @@ func search4MemberByIdInSlice(members []team_members.TeamMember, user_id string)
  * Returns authentication context (includes authorization header)
  */
 func getTeamMembersAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, team_members.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, team_members.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, team_members.ContextServerIndex, pco.server_index)
 }
 
@@ -367,7 +367,7 @@ func sortMapListRoles(roles []interface{}) {
  * Returns authentication context (includes authorization header)
  */
 func getTeamRolesAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, team_roles.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, team_roles.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, team_roles.ContextServerIndex, pco.server_index)
 }
 
@@ -372,7 +372,7 @@ func getUserWatchAttributes() []string {
  * Returns authentication context (includes authorization header)
  */
 func getUserAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, user.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, user.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, user.ContextServerIndex, pco.server_index)
 }
 
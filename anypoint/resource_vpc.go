@@ -366,7 +366,7 @@ func newVPCBody(d *schema.ResourceData) *vpc.VpcCore {
  * Returns authentication context (includes authorization header)
  */
 func getVPCAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, vpc.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, vpc.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, vpc.ContextServerIndex, pco.server_index)
 }
 
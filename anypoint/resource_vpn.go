@@ -339,7 +339,7 @@ func newVPNBody(d *schema.ResourceData) *vpn.VpnPostReqBody {
  * Returns authentication context (includes authorization header)
  */
 func getVPNAuthCtx(ctx context.Context, pco *ProviderConfOutput) context.Context {
-	tmp := context.WithValue(ctx, vpn.ContextAccessToken, pco.access_token)
+	tmp := context.WithValue(ctx, vpn.ContextAccessToken, pco.getAccessToken(ctx))
 	return context.WithValue(tmp, vpn.ContextServerIndex, pco.server_index)
 }
 
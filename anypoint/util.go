@@ -1,6 +1,7 @@
 package anypoint
 
 import (
+	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
@@ -211,6 +212,24 @@ func isComposedResourceId(id string, separator ...string) bool {
 	return strings.Contains(id, s)
 }
 
+/*
+Returns an import function that passes the id through once validated,
+the id must be composed of the given sub-ids, ex: []string{"org_id", "env_id"} for {org_id}/{env_id}
+*/
+func importComposedResourceIdPassthrough(parts []string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		s := DecomposeResourceId(d.Id())
+		valid := len(s) == len(parts)
+		for _, elem := range s {
+			valid = valid && elem != ""
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid id %q, expected {%s}", d.Id(), strings.Join(parts, "}"+COMPOSITE_ID_SEPARATOR+"{"))
+		}
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
 // decomposes a composite resource id
 func DecomposeResourceId(id string, separator ...string) []string {
 	s := COMPOSITE_ID_SEPARATOR
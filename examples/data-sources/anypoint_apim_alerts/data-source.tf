@@ -0,0 +1,5 @@
+data "anypoint_apim_alerts" "api01" {
+  org_id  = var.root_org
+  env_id  = var.env_id
+  apim_id = anypoint_apim_mule4.api01.id
+}
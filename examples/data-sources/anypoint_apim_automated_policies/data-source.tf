@@ -0,0 +1,4 @@
+data "anypoint_apim_automated_policies" "policies" {
+  org_id = var.root_org
+  env_id = var.env_id
+}
@@ -0,0 +1,12 @@
+data "anypoint_app_deployment_logs" "logs" {
+  org_id = var.root_org
+  env_id = var.env_id
+  deployment_id = "de32fc9d-6b25-4d6f-bd5e-cac32272b2f7"
+  levels = ["WARN", "ERROR"]
+  start_time = "2024-01-30T15:00:00Z"
+  max_lines = 200
+}
+
+output "logs" {
+  value = data.anypoint_app_deployment_logs.logs.text
+}
@@ -0,0 +1,46 @@
+data "anypoint_app_deployment_properties" "dev" {
+  properties_file = "${path.module}/config/dev.yaml"
+  secure_properties_file = "${path.module}/config/dev-secure.properties"
+}
+
+resource "anypoint_rtf_deployment" "deployment" {
+  org_id = var.root_org
+  env_id = var.env_id
+  name = "your-awesome-app"
+  application {
+    desired_state = "STARTED"
+    ref {
+      group_id = var.root_org
+      artifact_id = "your-awesome-app"
+      version = "1.0.0"
+      packaging = "jar"
+    }
+    configuration {
+      mule_agent_app_props_service {
+        properties = data.anypoint_app_deployment_properties.dev.properties
+        secure_properties = data.anypoint_app_deployment_properties.dev.secure_properties
+      }
+      mule_agent_logging_service {
+        scope_logging_configurations {
+          scope = "mule.package"
+          log_level = "DEBUG"
+        }
+      }
+    }
+  }
+  target {
+    provider = "MC"
+    target_id = var.fabrics_id
+    deployment_settings {
+      runtime {
+        version = "4.7.0:20e-java8"
+      }
+      resources {
+        cpu_reserved = "100m"
+        cpu_limit = "1000m"
+        memory_reserved = "1000Mi"
+        memory_limit = "1000Mi"
+      }
+    }
+  }
+}
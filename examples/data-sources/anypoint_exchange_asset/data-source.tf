@@ -0,0 +1,14 @@
+data "anypoint_exchange_asset" "spec" {
+  group_id = var.root_org
+  asset_id = "my-api-spec"
+  version_constraint = "~> 1.2"
+}
+
+resource "anypoint_apim_mule4" "api" {
+  org_id = var.root_org
+  env_id = var.env_id
+  asset_group_id = data.anypoint_exchange_asset.spec.group_id
+  asset_id = data.anypoint_exchange_asset.spec.asset_id
+  asset_version = data.anypoint_exchange_asset.spec.version
+  endpoint_uri = "https://my.endpoint.com"
+}
@@ -0,0 +1,9 @@
+data "anypoint_exchange_assets" "apps" {
+  org_id = var.root_org
+  params {
+    types = ["app"]
+    tags = ["payments"]
+    version_constraint = ">= 1.0.0, < 2.0.0"
+    limit = 50
+  }
+}
@@ -0,0 +1,10 @@
+provider "anypoint" {
+  profile = "default"
+}
+
+data "anypoint_provider_profile" "profile" {
+}
+
+output "default_environment" {
+  value = data.anypoint_provider_profile.profile.environment
+}
@@ -9,6 +9,16 @@ provider "anypoint" {
 
   access_token  = var.access_token      # optionally use ANYPOINT_ACCESS_TOKEN env var
 
+  # certificate based connected apps use the jwt bearer grant along with the client_id
+  jwt_private_key_path = var.jwt_private_key_path   # optionally use ANYPOINT_JWT_PRIVATE_KEY_PATH env var
+  jwt_subject = var.jwt_subject                     # optionally use ANYPOINT_JWT_SUBJECT env var
+
+  # a refresh token can be exchanged along with the client_id and client_secret
+  refresh_token = var.refresh_token     # optionally use ANYPOINT_REFRESH_TOKEN env var
+
+  # alternatively load the credentials of an anypoint cli profile from ~/.anypoint/credentials
+  profile = var.profile                 # optionally use ANYPOINT_PROFILE env var
+
   # You may need to change the anypoint control plane: use 'eu' or 'us'
   # by default the control plane is 'us'
   cplane= var.cplane                    # optionnaly use ANYPOINT_CPLANE env var
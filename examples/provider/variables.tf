@@ -13,6 +13,22 @@ variable "client_secret" {
 variable "access_token" {
 }
 
+variable "jwt_private_key_path" {
+  default = null
+}
+
+variable "jwt_subject" {
+  default = null
+}
+
+variable "refresh_token" {
+  default = null
+}
+
+variable "profile" {
+  default = null
+}
+
 variable "cplane" {
-  default = "us"
+  default = null
 }
\ No newline at end of file
@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{ENV_ID}/{API_ID}/{ALERT_ID}
+
+terraform import \
+  -var-file params.tfvars.json \    #variables file
+  anypoint_apim_alert.server_errors \                #resource name
+  aa1f55d6-213d-4f60-845c-207286484cd1/7074fcdd-9b23-4ab3-97c8-5db5f4adf17d/19250669/5d2b7c4e-8d1f-4a57-b7a3-1c2d3e4f5a6b      #resource ID
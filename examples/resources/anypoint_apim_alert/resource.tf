@@ -0,0 +1,26 @@
+resource "anypoint_apim_alert" "server_errors" {
+  org_id           = var.root_org
+  env_id           = var.env_id
+  apim_id          = anypoint_apim_mule4.api01.id
+  name             = "server errors"
+  type             = "response-code"
+  severity         = "CRITICAL"
+  response_codes   = ["500", "502", "503"]
+  threshold        = 10
+  period           = 5
+  email_recipients = ["ops@example.com"]
+}
+
+resource "anypoint_apim_alert" "rate_limit_violations" {
+  org_id          = var.root_org
+  env_id          = var.env_id
+  apim_id         = anypoint_apim_mule4.api01.id
+  name            = "rate limit violations"
+  type            = "policy-violation"
+  severity        = "WARNING"
+  policy_id       = anypoint_apim_policy_rate_limiting.policy01.id
+  threshold       = 100
+  period          = 1
+  period_unit     = "HOURS"
+  user_recipients = [var.owner_user_id]
+}
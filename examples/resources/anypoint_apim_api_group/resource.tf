@@ -0,0 +1,11 @@
+resource "anypoint_apim_api_group" "orders" {
+  org_id   = var.root_org
+  name     = "orders bundle"
+  asset_id = "orders-bundle"
+  versions {
+    name = "v1"
+  }
+  versions {
+    name = "v2"
+  }
+}
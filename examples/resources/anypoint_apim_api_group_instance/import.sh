@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{GROUP_ID}/{VERSION_ID}/{INSTANCE_ID}
+
+terraform import \
+  -var-file params.tfvars.json \    #variables file
+  anypoint_apim_api_group_instance.orders_v1_sandbox \                #resource name
+  aa1f55d6-213d-4f60-845c-207286484cd1/4521/6789/10234      #resource ID
@@ -0,0 +1,26 @@
+resource "anypoint_apim_api_group_instance" "orders_v1_sandbox" {
+  org_id     = var.root_org
+  group_id   = anypoint_apim_api_group.orders.group_id
+  version_id = anypoint_apim_api_group.orders.versions[0].version_id
+  env_id     = var.env_id
+  label      = "sandbox"
+  apim_ids = [
+    anypoint_apim_mule4.orders.id,
+    anypoint_apim_flexgateway.invoices.id,
+  ]
+  tiers {
+    name         = "bronze"
+    auto_approve = true
+    limits {
+      maximum_requests            = 100
+      time_period_in_milliseconds = 60000
+    }
+  }
+  tiers {
+    name = "gold"
+    limits {
+      maximum_requests            = 1000
+      time_period_in_milliseconds = 60000
+    }
+  }
+}
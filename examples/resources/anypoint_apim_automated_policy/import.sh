@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{AUTOMATED_POLICY_ID}
+
+terraform import \
+  -var-file params.tfvars.json \    #variables file
+  anypoint_apim_automated_policy.client_id_enforcement \                #resource name
+  aa1f55d6-213d-4f60-845c-207286484cd1/123456      #resource ID
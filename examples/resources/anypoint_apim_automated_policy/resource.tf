@@ -0,0 +1,48 @@
+resource "anypoint_apim_automated_policy" "client_id_enforcement" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  asset_version = "1.3.2"
+  rule_of_application {
+    technology = "mule4"
+    range_from = "4.1.1"
+  }
+  client_id_enforcement {
+    credentials_origin_has_http_basic_authentication_header = "customExpression"
+    client_id_expression                                    = "#[attributes.headers['client_id']]"
+    client_secret_expression                                = "#[attributes.headers['client_secret']]"
+  }
+}
+
+resource "anypoint_apim_automated_policy" "message_logging" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  asset_version = "2.0.1"
+  rule_of_application {
+    technology = "mule4"
+    range_from = "4.1.1"
+  }
+  message_logging {
+    logging_configuration {
+      name           = "default"
+      message        = "#[attributes.method] #[attributes.requestPath]"
+      level          = "INFO"
+      first_section  = true
+      second_section = false
+    }
+  }
+}
+
+resource "anypoint_apim_automated_policy" "custom" {
+  org_id         = var.root_org
+  env_id         = var.env_id
+  asset_group_id = var.root_org
+  asset_id       = "my-custom-policy"
+  asset_version  = "1.0.0"
+  rule_of_application {
+    technology = "flexGateway"
+    range_from = "1.4.0"
+  }
+  configuration_data = jsonencode({
+    header = "x-custom"
+  })
+}
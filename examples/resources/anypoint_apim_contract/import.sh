@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{ENV_ID}/{API_ID}/{CONTRACT_ID}
+
+terraform import \
+  -var-file params.tfvars.json \    #variables file
+  anypoint_apim_contract.app01_api01 \                #resource name
+  aa1f55d6-213d-4f60-845c-207286484cd1/7074fcdd-9b23-4ab3-97c8-5db5f4adf17d/19250669/7261390      #resource ID
@@ -0,0 +1,22 @@
+# the client id enforcement policy only accepts the credentials of applications with an approved contract
+resource "anypoint_apim_policy_client_id_enforcement" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.3.2"
+  configuration_data {
+    credentials_origin_has_http_basic_authentication_header = "customExpression"
+    client_id_expression                                    = "#[attributes.headers['client_id']]"
+    client_secret_expression                                = "#[attributes.headers['client_secret']]"
+  }
+}
+
+resource "anypoint_apim_contract" "app01_api01" {
+  org_id         = var.root_org
+  env_id         = var.env_id
+  apim_id        = anypoint_apim_mule4.api01.id
+  application_id = anypoint_exchange_client_application.app01.application_id
+  tier_id        = anypoint_apim_sla_tier.gold.tier_id
+  status         = "APPROVED" # set to REVOKED to revoke the access of the application
+}
@@ -7,4 +7,20 @@ resource "anypoint_apim_mule4" "api" {
   instance_label  = "my mule4 instance"
   description = "my description"
   endpoint_uri = "http://consumer.url"
-}
\ No newline at end of file
+}
+
+resource "anypoint_apim_mule4" "proxy" {
+  org_id                     = var.root_org
+  env_id                     = var.env_id
+  asset_group_id             = var.root_org
+  asset_id                   = "mule-app-test"
+  asset_version              = "1.0.0"
+  instance_label             = "my mule4 proxy"
+  endpoint_uri               = "https://implementation.internal.example.com/api"
+  endpoint_proxy_uri         = "http://0.0.0.0:8081/"
+  endpoint_deployment_type   = "CH2"
+  deployment_target_id       = var.private_space_id
+  deployment_type            = "CH2"
+  deployment_gateway_version = "4.6.0"
+  deployment_timeout         = 900
+}
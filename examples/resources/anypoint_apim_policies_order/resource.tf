@@ -0,0 +1,10 @@
+resource "anypoint_apim_policies_order" "api01_order" {
+  org_id  = var.root_org
+  env_id  = var.env_id
+  apim_id = anypoint_apim_mule4.api01.id
+  policy_ids = [
+    anypoint_apim_policy_client_id_enforcement.policy01.id,
+    anypoint_apim_policy_rate_limiting.policy01.id,
+    anypoint_apim_policy_message_logging.policy01.id,
+  ]
+}
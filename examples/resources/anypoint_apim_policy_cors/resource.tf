@@ -0,0 +1,19 @@
+resource "anypoint_apim_policy_cors" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.3.2"
+  configuration_data {
+    public_resource     = false
+    support_credentials = true
+    origin_groups {
+      origin_group_name      = "web"
+      origins                = ["https://www.example.com", "https://admin.example.com"]
+      access_control_max_age = 60
+      allowed_methods        = ["GET", "POST", "OPTIONS"]
+      headers                = ["content-type", "authorization"]
+      exposed_headers        = ["x-correlation-id"]
+    }
+  }
+}
@@ -134,4 +134,41 @@ resource "anypoint_apim_policy_custom" "policy_custom_05" {
     queuingLimit = 5
     exposeHeaders = true
   })
-}
\ No newline at end of file
+}
+#Tracing Policy Example (flex gateway only)
+#the asset_version is resolved to the latest version applicable to the flex gateway instance
+resource "anypoint_apim_policy_custom" "policy_custom_06" {
+  org_id = var.root_org
+  env_id = var.env_id
+  apim_id = anypoint_apim_flexgateway.api.id
+  technology = "flexGateway"
+  disabled = false
+  asset_group_id="68ef9520-24e9-4cf2-b2f5-620025690913"
+  asset_id="tracing"
+
+  configuration_data = jsonencode({
+    sampling = {
+      client = 100
+      random = 100
+      overall = 100
+    }
+    spanName = "#[attributes.method ++ ' ' ++ attributes.requestPath]"
+  })
+}
+
+#Header Transformation Policy Example (flex gateway only)
+resource "anypoint_apim_policy_custom" "policy_custom_07" {
+  org_id = var.root_org
+  env_id = var.env_id
+  apim_id = anypoint_apim_flexgateway.api.id
+  technology = "flexGateway"
+  disabled = false
+  asset_group_id="68ef9520-24e9-4cf2-b2f5-620025690913"
+  asset_id="header-transformation"
+
+  configuration_data = jsonencode({
+    inboundHeaders = [
+      { key = "x-source", value = "flex" }
+    ]
+  })
+}
@@ -0,0 +1,21 @@
+resource "anypoint_apim_policy_header_injection" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.3.1"
+  configuration_data {
+    inbound_headers {
+      key   = "x-forwarded-host"
+      value = "#[attributes.headers['host']]"
+    }
+    outbound_headers {
+      key   = "strict-transport-security"
+      value = "max-age=31536000"
+    }
+  }
+  pointcut_data {
+    method_regex       = ["GET", "POST"]
+    uri_template_regex = "/api/v1/.*"
+  }
+}
@@ -0,0 +1,14 @@
+resource "anypoint_apim_policy_http_caching" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.1.0"
+  configuration_data {
+    http_caching_key    = "#[attributes.requestPath]"
+    max_cache_entries   = 5000
+    ttl                 = 300
+    distributed         = true
+    invalidation_header = "x-cache-invalidate"
+  }
+}
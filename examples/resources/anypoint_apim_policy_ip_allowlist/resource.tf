@@ -0,0 +1,11 @@
+resource "anypoint_apim_policy_ip_allowlist" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.1.1"
+  configuration_data {
+    ip_expression = "#[attributes.headers['x-forwarded-for']]"
+    ips           = ["192.168.0.1", "10.0.0.0/16"]
+  }
+}
@@ -0,0 +1,10 @@
+resource "anypoint_apim_policy_ip_blocklist" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.1.1"
+  configuration_data {
+    ips = ["172.16.10.0/24"]
+  }
+}
@@ -0,0 +1,14 @@
+resource "anypoint_apim_policy_json_threat_protection" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.2.0"
+  configuration_data {
+    max_container_depth          = 10
+    max_string_value_length      = 4096
+    max_object_entry_name_length = 64
+    max_object_entry_count       = 100
+    max_array_element_count      = 1000
+  }
+}
@@ -0,0 +1,14 @@
+resource "anypoint_apim_policy_oauth2_token_introspection" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.2.0"
+  configuration_data {
+    introspection_url         = "https://auth.example.com/oauth2/introspect"
+    authorization_value       = var.introspection_authorization
+    validated_scopes          = ["read", "write"]
+    scope_validation_criteria = "AND"
+    expose_headers            = false
+  }
+}
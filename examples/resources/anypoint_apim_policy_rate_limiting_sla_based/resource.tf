@@ -0,0 +1,13 @@
+resource "anypoint_apim_policy_rate_limiting_sla_based" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.3.0"
+  configuration_data {
+    client_id_expression     = "#[attributes.headers['client_id']]"
+    client_secret_expression = "#[attributes.headers['client_secret']]"
+    expose_headers           = true
+    clusterizable            = true
+  }
+}
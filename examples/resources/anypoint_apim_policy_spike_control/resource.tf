@@ -0,0 +1,15 @@
+resource "anypoint_apim_policy_spike_control" "policy01" {
+  org_id        = var.root_org
+  env_id        = var.env_id
+  apim_id       = anypoint_apim_mule4.api01.id
+  disabled      = false
+  asset_version = "1.2.1"
+  configuration_data {
+    maximum_requests            = 100
+    time_period_in_milliseconds = 1000
+    delay_time_in_millis        = 500
+    delay_attempts              = 2
+    queuing_limit               = 10
+    expose_headers              = true
+  }
+}
@@ -0,0 +1,14 @@
+resource "anypoint_apim_promotion" "api01_prod" {
+  org_id           = var.root_org
+  source_env_id    = var.sandbox_env_id
+  source_apim_id   = anypoint_apim_mule4.api01.id
+  env_id           = var.production_env_id
+  instance_label   = "orders api"
+  include_policies = true
+  include_tiers    = true
+  include_alerts   = false
+}
+
+output "api01_prod_id" {
+  value = anypoint_apim_promotion.api01_prod.apim_id
+}
@@ -0,0 +1,17 @@
+resource "anypoint_apim_sla_tier" "gold" {
+  org_id       = var.root_org
+  env_id       = var.env_id
+  apim_id      = anypoint_apim_mule4.api01.id
+  name         = "gold"
+  description  = "1000 requests per minute, approved automatically"
+  auto_approve = true
+  limits {
+    maximum_requests            = 1000
+    time_period_in_milliseconds = 60000
+  }
+  limits {
+    maximum_requests            = 100000
+    time_period_in_milliseconds = 86400000
+    visible                     = false
+  }
+}
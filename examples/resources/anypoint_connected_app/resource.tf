@@ -55,4 +55,21 @@ resource "anypoint_connected_app" "my_conn_app_behalf_of_user" {
     scope {
         scope = "read:full"
     }
-}
\ No newline at end of file
+}
+resource "time_rotating" "secret_rotation" {
+  rotation_days = 90
+}
+
+resource "anypoint_connected_app" "my_conn_app_rotated_secret" {
+    name = "rotated secret"
+    grant_types = ["client_credentials"]
+    audience = "internal"
+
+    rotate_secret_on = {
+        rotation = time_rotating.secret_rotation.id
+    }
+
+    scope {
+        scope = "profile"
+    }
+}
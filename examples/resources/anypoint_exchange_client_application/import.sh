@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{APPLICATION_ID}
+
+terraform import \
+  -var-file params.tfvars.json \    #variables file
+  anypoint_exchange_client_application.app01 \                #resource name
+  aa1f55d6-213d-4f60-845c-207286484cd1/1987654      #resource ID
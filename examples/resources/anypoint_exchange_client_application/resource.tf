@@ -0,0 +1,17 @@
+resource "anypoint_exchange_client_application" "app01" {
+  org_id        = var.root_org
+  name          = "orders-portal"
+  description   = "Orders portal consuming the orders api"
+  url           = "https://orders.example.com"
+  redirect_uris = ["https://orders.example.com/callback"]
+  grant_types   = ["client_credentials", "authorization_code"]
+}
+
+output "app01_client_id" {
+  value = anypoint_exchange_client_application.app01.client_id
+}
+
+output "app01_client_secret" {
+  value     = anypoint_exchange_client_application.app01.client_secret
+  sensitive = true
+}
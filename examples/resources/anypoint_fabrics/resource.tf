@@ -4,3 +4,8 @@ resource "anypoint_fabrics" "fabrics" {
   region = "us-east-1"
   vendor = "eks"
 }
+
+resource "local_sensitive_file" "rtf_values" {
+  filename = "${path.module}/rtf-values.yaml"
+  content  = anypoint_fabrics.fabrics.helm_values
+}
@@ -0,0 +1,7 @@
+# In order for the import to work, you should provide a ID composed of the following:
+#  {ORG_ID}/{FABRICS_ID}/{ASSOCIATED_ORG_ID}/{ASSOCIATED_ENV_ID}
+
+terraform import \
+  -var-file params.tfvars.json \          #variables file
+  anypoint_fabrics_association.assoc \            #resource name
+  aa1f55d6-213d-4f60-845c-201282484cd1/4c641268-3917-45b0-acb8-f7cb0c0318ab/aa1f00d6-213d-4f60-845b-207286484bd1/7074fcee-9b23-4ab6-97e8-5de5f4aef17d    #resource ID
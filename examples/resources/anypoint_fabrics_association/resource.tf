@@ -0,0 +1,6 @@
+resource "anypoint_fabrics_association" "assoc" {
+  org_id = var.root_org
+  fabrics_id = "4c641268-3917-45b0-acb8-f7cb0c0318ab"
+  associated_org_id = "aa1f00d6-213d-4f60-845b-207286484bd1"
+  associated_env_id = "7074fcee-9b23-4ab6-97e8-5de5f4aef17d"
+}
@@ -1,6 +1,7 @@
 resource "anypoint_fabrics_associations" "assoc" {
   org_id = var.root_org
   fabrics_id = "4c641268-3917-45b0-acb8-f7cb0c0318ab"
+  on_destroy = "retain"
 
   # Associate a specific environment in a specific org
   associations {
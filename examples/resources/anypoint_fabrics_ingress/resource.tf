@@ -0,0 +1,8 @@
+resource "anypoint_fabrics_ingress" "ingress" {
+  org_id = var.root_org
+  fabrics_id = anypoint_fabrics.fabrics.id
+  domains = [
+    "*.apps.example.com",
+    "api.example.com"
+  ]
+}
@@ -0,0 +1,16 @@
+resource "anypoint_fabrics_log_forwarding" "logs" {
+  org_id = var.root_org
+  fabrics_id = anypoint_fabrics.fabrics.id
+  anypoint_monitoring = true
+  app_scoped_log_forwarding = false
+
+  outputs {
+    type = "splunk"
+    host = "splunk.example.com"
+    port = 8088
+    config = {
+      token = var.splunk_token
+      index = "rtf"
+    }
+  }
+}
@@ -0,0 +1,10 @@
+resource "anypoint_flexgateway_registration" "gw" {
+  org_id = var.root_org
+  env_id = var.env_id
+  name   = "my-flex-gateway"
+}
+
+resource "local_sensitive_file" "registration" {
+  filename = "${path.module}/conf/registration.yaml"
+  content  = anypoint_flexgateway_registration.gw.registration_yaml
+}
@@ -2,6 +2,7 @@ resource "anypoint_rtf_deployment" "deployment" {
   org_id = var.root_org
   env_id = var.env_id
   name   = "your-awesome-app"
+  require_healthy_target = true
   application {
     desired_state = "STARTED"
     ref {
@@ -58,4 +59,69 @@ resource "anypoint_rtf_deployment" "deployment" {
       }
     }
   }
-}
\ No newline at end of file
+}
+# blue/green rollout switching the api manager upstream to the new mule app
+resource "anypoint_rtf_deployment" "deployment_bg" {
+  org_id = var.root_org
+  env_id = var.env_id
+  name   = "your-blue-green-app"
+  application {
+    desired_state = "STARTED"
+    ref {
+      group_id    = var.root_org
+      artifact_id = "your-artifact-id"
+      version     = "1.0.2"
+      packaging   = "jar"
+    }
+    configuration {
+      mule_agent_app_props_service {
+        properties = {
+          props1 = "value01"
+        }
+      }
+      mule_agent_logging_service {
+        scope_logging_configurations {
+          scope     = "mule.package"
+          log_level = "INFO"
+        }
+      }
+    }
+  }
+
+  target {
+    provider = "MC"
+    target_id = var.fabrics_id
+    replicas = 1
+    deployment_settings {
+      runtime {
+        version = "4.7.0:20e-java8"
+      }
+      resources {
+        cpu_reserved = "100m"
+        cpu_limit = "1000m"
+        memory_reserved = "1000Mi"
+        memory_limit = "1000Mi"
+      }
+    }
+  }
+
+  strategy {
+    type   = "blue_green"
+    suffix = "-green"
+    health_check {
+      url               = "http://{app_name}.internal.example.net/health"
+      expected_status   = 200
+      interval          = 10
+      timeout           = 600
+      healthy_threshold = 3
+    }
+    apim {
+      org_id       = var.root_org
+      env_id       = var.env_id
+      apim_id      = "18888009"
+      upstream_id  = "4d2b1d3b-5d4a-4c0b-9f6e-7a0d1e2f3a4b"
+      upstream_uri = "http://{app_name}.internal.example.net"
+    }
+    retire_delay = 60
+  }
+}
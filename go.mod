@@ -3,6 +3,7 @@ module github.com/mulesoft-anypoint/terraform-provider-anypoint
 go 1.20
 
 require (
+	github.com/hashicorp/go-version v1.6.0
 	github.com/hashicorp/terraform-plugin-sdk/v2 v2.24.0
 	github.com/iancoleman/strcase v0.2.0
 	github.com/mulesoft-anypoint/anypoint-client-go/ame v1.0.0
@@ -36,6 +37,7 @@ require (
 	github.com/mulesoft-anypoint/anypoint-client-go/user_rolegroups v0.2.0
 	github.com/mulesoft-anypoint/anypoint-client-go/vpc v0.6.0
 	github.com/mulesoft-anypoint/anypoint-client-go/vpn v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -50,7 +52,6 @@ require (
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-plugin v1.4.4 // indirect
 	github.com/hashicorp/go-uuid v1.0.3 // indirect
-	github.com/hashicorp/go-version v1.6.0 // indirect
 	github.com/hashicorp/hcl/v2 v2.14.1 // indirect
 	github.com/hashicorp/logutils v1.0.0 // indirect
 	github.com/hashicorp/terraform-plugin-go v0.14.0 // indirect